@@ -0,0 +1,95 @@
+package ansi
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// base16Colours are the base16 colours used for each of the 16 ANSI colours, following base16-shell.
+var base16Colours = [16]int{
+	0x00, 0x08, 0x0B, 0x0A, 0x0D, 0x0E, 0x0C, 0x05,
+	0x03, 0x08, 0x0B, 0x0A, 0x0D, 0x0E, 0x0C, 0x07,
+}
+
+// ParseBase16 reads a base16 YAML colour scheme, with the base00 to base0F keys either at the top level
+// or under a palette key, as in the newer tinted-theming format.
+// The palette is named after the scheme or name key, and uses base05 for the foreground and cursor and base00 for the background.
+func ParseBase16(data []byte) (p *Palette, err error) {
+	type entry struct {
+		value string
+		line  int
+	}
+
+	var entries = make(map[string]entry)
+
+	var scanner = bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		var text = strings.TrimSpace(scanner.Text())
+		if text == "" || text[0] == '#' {
+			continue
+		}
+
+		var key, value, ok = strings.Cut(text, ":")
+		if !ok {
+			continue
+		}
+		entries[strings.ToLower(strings.TrimSpace(key))] = entry{value: yamlScalar(value), line: line}
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	p = &Palette{Name: entries["scheme"].value}
+	if p.Name == "" {
+		p.Name = entries["name"].value
+	}
+
+	var base [16]entry
+	for i := range base {
+		var key = fmt.Sprintf("base%02X", i)
+		var e, ok = entries[strings.ToLower(key)]
+		if !ok {
+			return nil, &PaletteError{Name: p.Name, Key: key, Err: ErrMissingKey}
+		}
+		base[i] = e
+	}
+
+	for i, b := range base16Colours {
+		var c, err = parseHex(base[b].value)
+		if err != nil {
+			return nil, &PaletteError{Name: p.Name, Key: fmt.Sprintf("base%02X", b), Line: base[b].line, Err: err}
+		}
+		p.Colours[i] = c
+	}
+	p.Foreground = p.Colours[7]
+	p.Background = p.Colours[0]
+	p.Cursor = p.Foreground
+
+	return
+}
+
+// LoadBase16 reads a base16 YAML colour scheme from r.
+func LoadBase16(r io.Reader) (*Palette, error) {
+	var data, err = io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return ParseBase16(data)
+}
+
+// yamlScalar returns a plain, single or double quoted YAML scalar without its quotes or trailing comment.
+func yamlScalar(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) > 0 && (s[0] == '"' || s[0] == '\'') {
+		if end := strings.IndexByte(s[1:], s[0]); end >= 0 {
+			return s[1 : end+1]
+		}
+	}
+	if comment := strings.Index(s, " #"); comment >= 0 {
+		s = s[:comment]
+	}
+	return strings.TrimSpace(s)
+}
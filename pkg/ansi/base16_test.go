@@ -0,0 +1,95 @@
+package ansi
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+const base16Scheme = `scheme: "Default Dark"
+author: "Chris Kempson (http://chriskempson.com)"
+base00: "181818" # background
+base01: "282828"
+base02: "383838"
+base03: "585858"
+base04: "b8b8b8"
+base05: "d8d8d8"
+base06: "e8e8e8"
+base07: "f8f8f8"
+base08: "ab4642"
+base09: "dc9656"
+base0A: "f7ca88"
+base0B: "a1b56c"
+base0C: "86c1b9"
+base0D: "7cafc2"
+base0E: "ba8baf"
+base0F: "a16946"
+`
+
+const tintedScheme = `system: "base16"
+name: 'Default Dark'
+variant: "dark"
+palette:
+  base00: "#181818"
+  base01: "#282828"
+  base02: "#383838"
+  base03: "#585858"
+  base04: "#b8b8b8"
+  base05: "#d8d8d8"
+  base06: "#e8e8e8"
+  base07: "#f8f8f8"
+  base08: "#ab4642"
+  base09: "#dc9656"
+  base0a: "#f7ca88"
+  base0b: "#a1b56c"
+  base0c: "#86c1b9"
+  base0d: "#7cafc2"
+  base0e: "#ba8baf"
+  base0f: "#a16946"
+`
+
+func TestParseBase16(t *testing.T) {
+	for name, scheme := range map[string]string{"Base16 scheme": base16Scheme, "Tinted theming scheme": tintedScheme} {
+		t.Run(
+			name, func(t *testing.T) {
+				var p, err = ParseBase16([]byte(scheme))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if p.Name != "Default Dark" {
+					t.Fatal("Wrong palette name", p.Name)
+				}
+				if p.Colours[0] != hex(0x181818) || p.Colours[1] != hex(0xAB4642) || p.Colours[9] != hex(0xAB4642) ||
+					p.Colours[4] != hex(0x7CAFC2) || p.Colours[8] != hex(0x585858) || p.Colours[15] != hex(0xF8F8F8) {
+					t.Fatal("Wrong palette colours", p.Colours)
+				}
+				if p.Foreground != hex(0xD8D8D8) || p.Background != hex(0x181818) || p.Cursor != p.Foreground {
+					t.Fatal("Wrong default colours")
+				}
+			},
+		)
+	}
+
+	t.Run(
+		"Missing key", func(t *testing.T) {
+			var _, err = ParseBase16([]byte(strings.Replace(base16Scheme, "base0C", "base0G", 1)))
+			var paletteErr *PaletteError
+			if !errors.As(err, &paletteErr) || !errors.Is(err, ErrMissingKey) || paletteErr.Key != "base0C" {
+				t.Fatal("Missing key wasn't reported", err)
+			}
+		},
+	)
+
+	t.Run(
+		"Invalid colour", func(t *testing.T) {
+			var _, err = ParseBase16([]byte(strings.Replace(base16Scheme, "7cafc2", "7cafcg", 1)))
+			var paletteErr *PaletteError
+			if !errors.As(err, &paletteErr) || !errors.Is(err, ErrInvalidColour) {
+				t.Fatal("Invalid colour wasn't reported", err)
+			}
+			if paletteErr.Key != "base0D" || paletteErr.Line != 16 || paletteErr.Name != "Default Dark" {
+				t.Fatal("Error doesn't point at the invalid colour", err)
+			}
+		},
+	)
+}
@@ -2,9 +2,7 @@ package ansi
 
 import (
 	"errors"
-	"io"
-	"strconv"
-	"strings"
+	"unicode/utf8"
 )
 
 const (
@@ -14,38 +12,249 @@ const (
 	Separator  = ';'       // 59
 )
 
+const (
+	BellCode             = 7
+	OSCCode              = byte(']')  // 93
+	DCSCode              = byte('P')  // 80
+	SOSCode              = byte('X')  // 88
+	PMCode               = byte('^')  // 94
+	APCCode              = byte('_')  // 95
+	StringTerminatorCode = byte('\\') // 92
+
+	// C1Offset is the difference between an 8-bit C1 control and the byte that follows an escape in its 7-bit form.
+	C1Offset = 0x40
+
+	// MaxScanLength is the number of bytes ansi.ScanCodes reads looking for the end of a sequence or control string.
+	// Past it, the introducer is returned as text instead, so that a stray introducer can't hold back the rest
+	// of a stream. It is below bufio.MaxScanTokenSize, so a bufio.Scanner never fails with a token that is too long.
+	MaxScanLength = 1 << 15
+)
+
 // ErrByteOverflow indicates that the parsed value is under 0 or above 255.
 var ErrByteOverflow = errors.New("invalid byte value")
 
 // isSequenceStart is a helper function to check if the data slice starts at an ANSI escape sequence
+// or control string.
 func isSequenceStart(data []byte) bool {
-	return len(data) >= 2 && data[0] == EscapeCode && data[1] == StartCode
+	var _, n = sequenceStart(data, false)
+	return n > 0
 }
 
-// ScanCodes will tokenize a string into ANSI colour sequences and normal text.
-// Sequences can be checked with ansi.IsSequence.
+// sequenceStart returns the 7-bit introducer of the sequence or control string at the start of data,
+// along with the length of the introducer in data.
+// If c1 is set, single byte C1 introducers are accepted as well.
+// Returns a length of 0 if data doesn't start with a sequence.
+func sequenceStart(data []byte, c1 bool) (code byte, n int) {
+	switch {
+	case len(data) >= 2 && data[0] == EscapeCode && isIntroducer(data[1]):
+		return data[1], 2
+	case c1 && len(data) >= 1 && isC1(data[0]) && isIntroducer(data[0]-C1Offset):
+		return data[0] - C1Offset, 1
+	default:
+		return 0, 0
+	}
+}
+
+// isIntroducer checks if b introduces a control sequence or control string when following an escape.
+func isIntroducer(b byte) bool {
+	return b == StartCode || isStringStart(b)
+}
+
+// isStringStart checks if b introduces a control string when following an escape.
+func isStringStart(b byte) bool {
+	return b == OSCCode || b == DCSCode || b == SOSCode || b == PMCode || b == APCCode
+}
+
+// isC1 checks if b is in the 0x80–0x9F range of 8-bit C1 controls.
+func isC1(b byte) bool {
+	return b >= 0x80 && b <= 0x9F
+}
+
+// isPartialSequenceStart is a helper function to check if the data slice is too short to tell
+// whether it starts an ANSI escape sequence.
+func isPartialSequenceStart(data []byte) bool {
+	return len(data) == 1 && data[0] == EscapeCode
+}
+
+// isPartialRune is a helper function to check if the data slice starts with the beginning of a UTF-8 character
+// that is cut short by the end of the slice.
+func isPartialRune(data []byte) bool {
+	return len(data) > 0 && data[0] >= utf8.RuneSelf && !utf8.FullRune(data)
+}
+
+// partialRuneLength returns the length of the incomplete UTF-8 character at the end of data, or 0 if it ends with a
+// complete one.
+func partialRuneLength(data []byte) int {
+	for n := 1; n < utf8.UTFMax && n <= len(data); n++ {
+		var b = data[len(data)-n]
+		if b < utf8.RuneSelf {
+			return 0
+		}
+		if utf8.RuneStart(b) {
+			if isPartialRune(data[len(data)-n:]) {
+				return n
+			}
+			return 0
+		}
+	}
+	return 0
+}
+
+// runeLength returns the length of the UTF-8 character at the start of data, or 1 if it isn't valid UTF-8.
+func runeLength(data []byte) int {
+	var _, n = utf8.DecodeRune(data)
+	return max(n, 1)
+}
+
+// scanSequence returns the length of the control sequence at the start of data,
+// following the ECMA-48 grammar of parameter bytes, then intermediate bytes, then a final byte.
+// The introducer takes the first start bytes of data.
+// Complete reports whether the final byte was found.
+// Incomplete sequences end either with the data or right before an invalid byte.
+func scanSequence(data []byte, start int) (n int, complete bool) {
+	n = start
+	for n < len(data) && isParameterByte(data[n]) {
+		n++
+	}
+	for n < len(data) && isIntermediateByte(data[n]) {
+		n++
+	}
+	if n < len(data) && isFinalByte(data[n]) {
+		return n + 1, true
+	}
+	return n, false
+}
+
+// scanString returns the length of the control string at the start of data,
+// terminated by either a bell or a string terminator (ESC \\).
+// The introducer takes the first start bytes of data.
+// Escapes are doubled inside tmux passthrough strings (ESC P tmux; ...), so a doubled escape is kept as data,
+// and bells are no longer terminators in a device control string after one, since they belong to the wrapped sequence.
+// If c1 is set, the 8-bit string terminator is accepted as well, unless it's part of a UTF-8 character.
+// Complete reports whether the terminator was found.
+// Incomplete strings end either with the data or right before an escape that isn't a terminator.
+func scanString(data []byte, code byte, start int, c1 bool) (n int, complete bool) {
+	var passthrough bool
+
+	for n = start; n < len(data); n++ {
+		switch data[n] {
+		case BellCode:
+			if !passthrough || code != DCSCode {
+				return n + 1, true
+			}
+		case EscapeCode:
+			if n+1 == len(data) {
+				return len(data), false
+			}
+			switch data[n+1] {
+			case StringTerminatorCode:
+				return n + 2, true
+			case EscapeCode:
+				passthrough = true
+				n++
+			default:
+				return n, false
+			}
+		default:
+			if !c1 || data[n] < utf8.RuneSelf {
+				continue
+			}
+			if data[n] == StringTerminatorCode+C1Offset {
+				return n + 1, true
+			}
+			if isPartialRune(data[n:]) {
+				return len(data), false
+			}
+			n += runeLength(data[n:]) - 1
+		}
+	}
+	return
+}
+
+// scanControl returns the length of the sequence or control string at the start of data.
+func scanControl(data []byte, c1 bool) (n int, complete bool) {
+	var code, start = sequenceStart(data, c1)
+	if code == StartCode {
+		return scanSequence(data, start)
+	}
+	return scanString(data, code, start, c1)
+}
+
+// isParameterByte checks if b is in the 0x30–0x3F range of control sequence parameters.
+func isParameterByte(b byte) bool {
+	return b >= 0x30 && b <= 0x3F
+}
+
+// isIntermediateByte checks if b is in the 0x20–0x2F range of control sequence intermediates.
+func isIntermediateByte(b byte) bool {
+	return b >= 0x20 && b <= 0x2F
+}
+
+// isFinalByte checks if b is in the 0x40–0x7E range of control sequence final bytes.
+func isFinalByte(b byte) bool {
+	return b >= 0x40 && b <= 0x7E
+}
+
+// ScanCodes will tokenize a string into ANSI control sequences, control strings and normal text.
+// Colour sequences can be checked with ansi.IsSequence, and any sequence can be classified with ansi.Classify.
+// Sequences split across reads are only returned once complete, unless there is no more data,
+// in which case they are returned as text.
+// Sequences that are still incomplete after ansi.MaxScanLength bytes have their introducer returned as text.
+// Malformed sequences are cut short at the first invalid byte and returned as text.
+// Any remaining data is returned as the last token at EOF, the same as bufio.ScanLines.
 // This function implements bufio.SplitFunc.
 func ScanCodes(data []byte, atEOF bool) (advance int, token []byte, err error) {
-	if atEOF {
-		err = io.EOF
+	return scanCodes(data, atEOF, false)
+}
+
+// ScanCodesC1 works the same as ansi.ScanCodes, but also recognises the 8-bit C1 introducers
+// CSI (0x9B), OSC (0x9D), DCS (0x90), SOS (0x98), PM (0x9E) and APC (0x9F), and the string terminator (0x9C).
+// Text is read as UTF-8, so bytes that are part of a valid UTF-8 character are never treated as C1 controls.
+// Its tokens are checked with ansi.IsSequenceC1, classified with ansi.ClassifyC1 and parsed with ansi.StrictC1 or ansi.LenientC1.
+// This function implements bufio.SplitFunc.
+func ScanCodesC1(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	return scanCodes(data, atEOF, true)
+}
+
+// scanCodes implements ansi.ScanCodes, with C1 introducers if c1 is set.
+func scanCodes(data []byte, atEOF bool, c1 bool) (advance int, token []byte, err error) {
+	if atEOF && len(data) == 0 {
 		return
 	}
 
 	advance = len(data)
 
-	if isSequenceStart(data) {
-		for i := 0; i < advance; i++ {
-			if data[i] == EndCode {
-				advance = i + 1
-				break
-			}
+	if _, start := sequenceStart(data, c1); start > 0 {
+		var n, complete = scanControl(data, c1)
+		switch {
+		case complete || n < len(data) || atEOF:
+			advance = n
+		case len(data) >= MaxScanLength:
+			advance = start
+		default:
+			return 0, nil, nil
 		}
+	} else if !atEOF && isPartialSequenceStart(data) {
+		return 0, nil, nil
 	} else {
 		for i := 0; i < advance; i++ {
-			if isSequenceStart(data[i:]) {
+			if _, start := sequenceStart(data[i:], c1); start > 0 {
+				advance = i
+				break
+			}
+
+			if !atEOF && (isPartialSequenceStart(data[i:]) || (c1 && isPartialRune(data[i:]))) {
 				advance = i
 				break
 			}
+
+			if c1 && data[i] >= utf8.RuneSelf {
+				i += runeLength(data[i:]) - 1
+			}
+		}
+
+		if advance == 0 {
+			return 0, nil, nil
 		}
 	}
 
@@ -56,29 +265,51 @@ func ScanCodes(data []byte, atEOF bool) (advance int, token []byte, err error) {
 // IsSequence checks wheter the given slice is a complete ANSI colour sequence.
 // It is meant to be used along with ansi.ScanCodes.
 func IsSequence(b []byte) bool {
-	return isSequenceStart(b) && b[len(b)-1] == EndCode
+	return Classify(b) == KindSGR
+}
+
+// IsSequenceC1 checks wheter the given slice is a complete ANSI colour sequence, including the 8-bit CSI form.
+// It is meant to be used along with ansi.ScanCodesC1.
+func IsSequenceC1(b []byte) bool {
+	return ClassifyC1(b) == KindSGR
 }
 
 // ParseColourCodes will parse Colour codes from an ANSI sequence.
+// Every parameter, including sub-parameters, becomes a separate Colour.
+// Errors are returned as a *ParseError.
 // It is meant to be used along with ansi.ScanCodes and ansi.IsSequence.
 func ParseColourCodes(sequence []byte) (res []Colour, err error) {
-	var strCodes = strings.Split(string(sequence[2:len(sequence)-1]), string(Separator))
-	res = make([]Colour, len(strCodes))
+	return Strict.ParseColourCodes(sequence)
+}
 
-	for i := 0; i < len(res); i++ {
-		var n int
+// ParseColourCodes will parse Colour codes from an ANSI sequence.
+// Every parameter, including sub-parameters, becomes a separate Colour.
+// In Lenient mode, values above 255 are skipped.
+// Errors are returned as a *ParseError.
+func (m ParseMode) ParseColourCodes(sequence []byte) (res []Colour, err error) {
+	var params []Parameter
+	params, err = m.ParseParameters(sequence)
+	if err != nil {
+		return
+	}
 
-		n, err = strconv.Atoi(strCodes[i])
-		if err != nil {
-			return
-		}
+	if sequence[len(sequence)-1] != EndCode {
+		err = newParseError(sequence, len(sequence)-1, -1, CategoryUnknownFinal, ErrUnknownFinal)
+		return
+	}
+
+	res = make([]Colour, 0, len(params))
+	for i := 0; i < len(params); i++ {
+		if params[i].Value > 255 {
+			if m.lenient() {
+				continue
+			}
 
-		if n > 255 || n < 0 {
-			err = ErrByteOverflow
+			err = newParseError(sequence, parameterOffset(sequence, i, m.c1()), i, CategoryOverflow, ErrByteOverflow)
 			return
 		}
 
-		res[i] = Colour(n)
+		res = append(res, Colour(params[i].Value))
 	}
 
 	return
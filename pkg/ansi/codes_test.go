@@ -4,7 +4,10 @@ import (
 	"bufio"
 	"bytes"
 	"fmt"
+	"io"
+	"strings"
 	"testing"
+	"testing/iotest"
 )
 
 func Test_isSequenceStart(t *testing.T) {
@@ -82,6 +85,389 @@ func Test_ScanCodes(t *testing.T) {
 	)
 }
 
+func Test_ScanCodesPartial(t *testing.T) {
+	t.Run(
+		"Incomplete sequence", func(t *testing.T) {
+			var advance, token, err = ScanCodes([]byte("\x1b[3"), false)
+			if err != nil || advance != 0 || token != nil {
+				t.Fatal("Incomplete sequence should request more data")
+			}
+
+			advance, token, err = ScanCodes([]byte("\x1b"), false)
+			if err != nil || advance != 0 || token != nil {
+				t.Fatal("Lone escape should request more data")
+			}
+
+			advance, token, err = ScanCodes([]byte("\x1b]8;;http://a\x1b"), false)
+			if err != nil || advance != 0 || token != nil {
+				t.Fatal("Incomplete control string should request more data")
+			}
+		},
+	)
+
+	t.Run(
+		"Text before incomplete sequence", func(t *testing.T) {
+			var advance, token, err = ScanCodes([]byte("Hello\x1b"), false)
+			if err != nil || advance != 5 || string(token) != "Hello" {
+				t.Fatal("Text shouldn't include a possible sequence start")
+			}
+
+			advance, token, err = ScanCodes([]byte("Hello\x1b[3"), false)
+			if err != nil || advance != 5 || string(token) != "Hello" {
+				t.Fatal("Text shouldn't include an incomplete sequence")
+			}
+		},
+	)
+
+	t.Run(
+		"Escape without sequence", func(t *testing.T) {
+			var advance, token, err = ScanCodes([]byte("\x1b(Bm"), false)
+			if err != nil || advance != 4 || string(token) != "\x1b(Bm" {
+				t.Fatal("Escape not followed by a sequence start should be text")
+			}
+		},
+	)
+
+	t.Run(
+		"Small buffer", func(t *testing.T) {
+			var (
+				data    = []byte("Hello\x1b[31mWorld")
+				buffer  []byte
+				tokens  []string
+				pending = 0
+			)
+
+			// Feed data in chunks of 3 bytes, the same way bufio.Scanner would
+			for pending < len(data) || len(buffer) > 0 {
+				if pending < len(data) {
+					var end = min(pending+3, len(data))
+					buffer = append(buffer, data[pending:end]...)
+					pending = end
+				}
+
+				for len(buffer) > 0 {
+					var advance, token, _ = ScanCodes(buffer, false)
+					if advance == 0 {
+						break
+					}
+					tokens = append(tokens, string(token))
+					buffer = buffer[advance:]
+				}
+			}
+
+			for _, token := range tokens {
+				if token == "1m" || token == "\x1b[3" {
+					t.Fatal("Sequence split across reads was corrupted")
+				}
+			}
+
+			var found bool
+			for _, token := range tokens {
+				found = found || token == "\x1b[31m"
+			}
+
+			if !found {
+				t.Fatal("Sequence split across reads wasn't reassembled")
+			}
+		},
+	)
+}
+
+func Test_ScanCodesUnterminated(t *testing.T) {
+	var input = "log\x1b]0;oops" + strings.Repeat("plain text\n", 9000) + "\x1b[31mred\x1b[0m"
+	var scanner = bufio.NewScanner(strings.NewReader(input))
+	scanner.Split(ScanCodes)
+
+	var actual strings.Builder
+	var sequences int
+	for scanner.Scan() {
+		actual.Write(scanner.Bytes())
+		if IsSequence(scanner.Bytes()) {
+			sequences++
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		t.Fatal("Stray introducer stopped the scanner", err)
+	}
+
+	if actual.String() != input || sequences != 2 {
+		t.Fatal("Text after a stray introducer wasn't scanned")
+	}
+
+	var advance, token, _ = ScanCodes([]byte("\x1b]0;"+strings.Repeat("a", MaxScanLength)), false)
+	if advance != 2 || string(token) != "\x1b]" || Classify(token) != KindText {
+		t.Fatalf("Introducer should be returned as text, got %q", token)
+	}
+}
+
+func Test_ScanCodesEOF(t *testing.T) {
+	t.Run(
+		"Trailing text", func(t *testing.T) {
+			var advance, token, err = ScanCodes([]byte("Hello"), true)
+			if err != nil || advance != 5 || string(token) != "Hello" {
+				t.Fatal("Trailing text should be flushed at EOF")
+			}
+		},
+	)
+
+	t.Run(
+		"Trailing incomplete sequence", func(t *testing.T) {
+			var advance, token, err = ScanCodes([]byte("\x1b[3"), true)
+			if err != nil || advance != 3 || string(token) != "\x1b[3" {
+				t.Fatal("Incomplete sequence should be flushed at EOF")
+			}
+
+			if IsSequence(token) {
+				t.Fatal("Incomplete sequence shouldn't be a sequence")
+			}
+
+			advance, token, err = ScanCodes([]byte("Hello\x1b"), true)
+			if err != nil || advance != 6 || string(token) != "Hello\x1b" {
+				t.Fatal("Lone escape should be flushed as text at EOF")
+			}
+		},
+	)
+
+	t.Run(
+		"No data", func(t *testing.T) {
+			var advance, token, err = ScanCodes(nil, true)
+			if err != nil || advance != 0 || token != nil {
+				t.Fatal("Empty data at EOF should stop scanning")
+			}
+		},
+	)
+}
+
+func Test_ScanCodesReaders(t *testing.T) {
+	var input = "\x1b[1;31mHello\x1b[0m, \x1b[38;5;208mWorld\x1b[0m!\x1b[3"
+	var expected = []string{
+		"\x1b[1;31m", "Hello", "\x1b[0m", ", ", "\x1b[38;5;208m", "World", "\x1b[0m", "!", "\x1b[3",
+	}
+
+	var readers = map[string]func(io.Reader) io.Reader{
+		"Plain":   func(r io.Reader) io.Reader { return r },
+		"OneByte": iotest.OneByteReader,
+		"Half":    iotest.HalfReader,
+		"DataErr": iotest.DataErrReader,
+		"OneByteDataErr": func(r io.Reader) io.Reader {
+			return iotest.DataErrReader(iotest.OneByteReader(r))
+		},
+	}
+
+	for name, wrap := range readers {
+		t.Run(
+			name, func(t *testing.T) {
+				var scanner = bufio.NewScanner(wrap(strings.NewReader(input)))
+				scanner.Buffer(make([]byte, 2), 64)
+				scanner.Split(ScanCodes)
+
+				var actual []byte
+				var sequences int
+				for scanner.Scan() {
+					var token = scanner.Bytes()
+					actual = append(actual, token...)
+					if IsSequence(token) {
+						sequences++
+					}
+				}
+
+				if scanner.Err() != nil {
+					t.Fatal("Errored while scanning")
+				}
+
+				if string(actual) != input {
+					t.Fatalf("Scanned data doesn't match input, got %q", actual)
+				}
+
+				if sequences != 4 {
+					t.Fatalf("Expected 4 sequences, got %d", sequences)
+				}
+			},
+		)
+	}
+
+	t.Run(
+		"Tokens", func(t *testing.T) {
+			var scanner = bufio.NewScanner(strings.NewReader(input))
+			scanner.Split(ScanCodes)
+
+			var actual []string
+			for scanner.Scan() {
+				actual = append(actual, scanner.Text())
+			}
+
+			if len(actual) != len(expected) {
+				t.Fatalf("Expected %q, got %q", expected, actual)
+			}
+
+			for i := range expected {
+				if actual[i] != expected[i] {
+					t.Fatalf("Expected %q, got %q", expected, actual)
+				}
+			}
+		},
+	)
+}
+
+func Test_ScanCodesCSI(t *testing.T) {
+	var tests = []struct {
+		name     string
+		input    string
+		expected []string
+	}{
+		{
+			name:     "Erase line",
+			input:    "\x1b[2KHello, m\x1b[0m",
+			expected: []string{"\x1b[2K", "Hello, m", "\x1b[0m"},
+		},
+		{
+			name:     "Cursor position",
+			input:    "\x1b[10;5HHome\x1b[?25lmore",
+			expected: []string{"\x1b[10;5H", "Home", "\x1b[?25l", "more"},
+		},
+		{
+			name:     "Intermediate bytes",
+			input:    "\x1b[2 qm",
+			expected: []string{"\x1b[2 q", "m"},
+		},
+		{
+			name:     "Control strings",
+			input:    "\x1b]0;title\x07Hello\x1b]8;;http://a\x1b\\link\x1b]8;;\x1b\\\x1b_apc m\x07",
+			expected: []string{"\x1b]0;title\x07", "Hello", "\x1b]8;;http://a\x1b\\", "link", "\x1b]8;;\x1b\\", "\x1b_apc m\x07"},
+		},
+		{
+			name:     "Passthrough",
+			input:    "\x1bPtmux;\x1b\x1b]52;c;eA==\x07\x1b\\text",
+			expected: []string{"\x1bPtmux;\x1b\x1b]52;c;eA==\x07\x1b\\", "text"},
+		},
+		{
+			name:     "Unterminated string",
+			input:    "\x1b]0;title\x1b[31mred",
+			expected: []string{"\x1b]0;title", "\x1b[31m", "red"},
+		},
+		{
+			name:     "Malformed",
+			input:    "\x1b[31\nHello\x1b[1;\x1b[0m",
+			expected: []string{"\x1b[31", "\nHello", "\x1b[1;", "\x1b[0m"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.name, func(t *testing.T) {
+				var scanner = bufio.NewScanner(strings.NewReader(test.input))
+				scanner.Split(ScanCodes)
+
+				var actual []string
+				for scanner.Scan() {
+					actual = append(actual, scanner.Text())
+				}
+
+				if len(actual) != len(test.expected) {
+					t.Fatalf("Expected %q, got %q", test.expected, actual)
+				}
+
+				for i := range actual {
+					if actual[i] != test.expected[i] {
+						t.Fatalf("Expected %q, got %q", test.expected, actual)
+					}
+				}
+			},
+		)
+	}
+}
+
+func Test_ScanCodesC1(t *testing.T) {
+	var tests = []struct {
+		name     string
+		input    string
+		expected []string
+	}{
+		{
+			name:     "Introducers",
+			input:    "\x9b31mred\x9d0;title\x9ctext\x90+q\x1b\\\x9b0m",
+			expected: []string{"\x9b31m", "red", "\x9d0;title\x9c", "text", "\x90+q\x1b\\", "\x9b0m"},
+		},
+		{
+			// ě is C4 9B and ĝ is C4 9D, which contain the CSI and OSC bytes
+			name:     "UTF-8",
+			input:    "děkuji ĝi\x9b1m\x9d0;ŜĜ\x07",
+			expected: []string{"děkuji ĝi", "\x9b1m", "\x9d0;ŜĜ\x07"},
+		},
+		{
+			name:     "Mixed",
+			input:    "\x1b[1m\x9b2m",
+			expected: []string{"\x1b[1m", "\x9b2m"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.name, func(t *testing.T) {
+				for name, reader := range map[string]io.Reader{
+					"Whole":   strings.NewReader(test.input),
+					"OneByte": iotest.OneByteReader(strings.NewReader(test.input)),
+				} {
+					var scanner = bufio.NewScanner(reader)
+					scanner.Split(ScanCodesC1)
+
+					var actual []string
+					var sequences int
+					for scanner.Scan() {
+						actual = append(actual, scanner.Text())
+						if ClassifyC1(scanner.Bytes()) != KindText {
+							sequences++
+						}
+					}
+
+					if strings.Join(actual, "") != test.input {
+						t.Fatalf("%s: scanned data doesn't match input, got %q", name, actual)
+					}
+
+					var expectedSequences int
+					for _, token := range test.expected {
+						if ClassifyC1([]byte(token)) != KindText {
+							expectedSequences++
+						}
+					}
+
+					if sequences != expectedSequences {
+						t.Fatalf("%s: expected %q, got %q", name, test.expected, actual)
+					}
+
+					if name == "Whole" && strings.Join(actual, "\n") != strings.Join(test.expected, "\n") {
+						t.Fatalf("Expected %q, got %q", test.expected, actual)
+					}
+				}
+			},
+		)
+	}
+
+	t.Run(
+		"Disabled", func(t *testing.T) {
+			var advance, _, _ = ScanCodes([]byte("\x9b31mred"), false)
+			if advance != 7 {
+				t.Fatal("C1 introducers should be text unless enabled")
+			}
+		},
+	)
+
+	t.Run(
+		"Partial character", func(t *testing.T) {
+			var advance, token, _ = ScanCodesC1([]byte("ab\xc4"), false)
+			if advance != 2 || string(token) != "ab" {
+				t.Fatal("Partial UTF-8 character should wait for more data")
+			}
+
+			advance, token, _ = ScanCodesC1([]byte("\xc4"), false)
+			if advance != 0 || token != nil {
+				t.Fatal("Partial UTF-8 character should request more data")
+			}
+		},
+	)
+}
+
 func Test_IsSequence(t *testing.T) {
 	if IsSequence([]byte{StartCode, byte('3'), byte('1'), EndCode}) {
 		t.Fatal("Sequence isn't being escaped")
@@ -94,6 +480,14 @@ func Test_IsSequence(t *testing.T) {
 	if IsSequence([]byte{EscapeCode, StartCode, byte('3'), byte('1')}) {
 		t.Fatal("Sequence end not being checked")
 	}
+
+	if IsSequence([]byte("\x1b[2K")) || IsSequence([]byte("\x1b[?1m")) {
+		t.Fatal("Only SGR sequences are colour sequences")
+	}
+
+	if IsSequence([]byte("\x9b31m")) || !IsSequenceC1([]byte("\x9b31m")) {
+		t.Fatal("8-bit sequences should only be detected in C1 mode")
+	}
 }
 
 func Test_ParseColourCodes(t *testing.T) {
@@ -234,55 +628,94 @@ var benches = []benchScan{
 	},
 }
 
+// benchParser is a way of parsing the sequences found while benchmarking.
+type benchParser struct {
+	name  string
+	parse func(p *Painter, token []byte)
+}
+
+var benchParsers = []benchParser{
+	{
+		name: "Colours",
+		parse: func(_ *Painter, token []byte) {
+			_ = MustParseColourCodes(token)
+		},
+	},
+	{
+		name: "Parameters",
+		parse: func(_ *Painter, token []byte) {
+			var buffer [MaxParameters]Parameter
+			var _, err = AppendParameters(buffer[:0], token)
+			if err != nil {
+				panic(err)
+			}
+		},
+	},
+	{
+		name: "Painter",
+		parse: func(p *Painter, token []byte) {
+			if err := p.Apply(token); err != nil {
+				panic(err)
+			}
+		},
+	},
+}
+
 func BenchmarkParseColourCodes(b *testing.B) {
 	for _, bench := range benches {
-		b.Run(
-			bench.name, func(b *testing.B) {
-				b.ReportAllocs()
-
-				for i := 0; i < b.N; i++ {
-					var data = bench.test
-					for len(data) > 0 {
-						var advance, token, _ = ScanCodes(data, false)
-						data = data[advance:]
-
-						if advance == 0 {
-							panic("Avoid infinite loop")
-						}
-
-						if IsSequence(token) {
-							_ = MustParseColourCodes(token)
+		for _, parser := range benchParsers {
+			b.Run(
+				bench.name+"/"+parser.name, func(b *testing.B) {
+					b.ReportAllocs()
+
+					var p = DefaultPainter()
+					for i := 0; i < b.N; i++ {
+						var data = bench.test
+						for len(data) > 0 {
+							var advance, token, _ = ScanCodes(data, false)
+							data = data[advance:]
+
+							if advance == 0 {
+								panic("Avoid infinite loop")
+							}
+
+							if IsSequence(token) {
+								parser.parse(p, token)
+							}
 						}
 					}
-				}
-			},
-		)
+				},
+			)
+		}
 	}
 }
 
 func BenchmarkParseColourCodesWithScanner(b *testing.B) {
 	for _, bench := range benches {
-		b.Run(
-			bench.name, func(b *testing.B) {
-				b.ReportAllocs()
-
-				for i := 0; i < b.N; i++ {
-					var (
-						data    = bench.test
-						reader  = bytes.NewReader(data)
-						scanner = bufio.NewScanner(reader)
-					)
-					scanner.Split(ScanCodes)
-
-					for scanner.Scan() {
-						var token = scanner.Bytes()
-						if IsSequence(token) {
-							_ = MustParseColourCodes(token)
+		for _, parser := range benchParsers {
+			b.Run(
+				bench.name+"/"+parser.name, func(b *testing.B) {
+					b.ReportAllocs()
+
+					var p = DefaultPainter()
+					for i := 0; i < b.N; i++ {
+						var (
+							data    = bench.test
+							reader  = bytes.NewReader(data)
+							scanner = bufio.NewScanner(reader)
+						)
+						scanner.Split(ScanCodes)
+
+						for scanner.Scan() {
+							var token = scanner.Bytes()
+							if IsSequence(token) {
+								parser.parse(p, token)
+							}
 						}
 					}
-				}
-			},
-		)
+				},
+			)
+		}
 	}
 }
 
@@ -1,5 +1,7 @@
 package ansi
 
+import "image/color"
+
 type Colour byte
 
 const (
@@ -8,9 +10,26 @@ const (
 )
 
 const (
-	NORMAL Colour = 0
-	BOLD   Colour = 1
-	ITALIC Colour = 2
+	NORMAL        Colour = 0
+	BOLD          Colour = 1
+	FAINT         Colour = 2
+	ITALIC        Colour = 3
+	UNDERLINE     Colour = 4
+	SLOW_BLINK    Colour = 5
+	RAPID_BLINK   Colour = 6
+	REVERSE       Colour = 7
+	CONCEAL       Colour = 8
+	STRIKETHROUGH Colour = 9
+
+	DOUBLE_UNDERLINE  Colour = 21
+	NORMAL_INTENSITY  Colour = 22
+	NOT_ITALIC        Colour = 23
+	NOT_UNDERLINED    Colour = 24
+	NOT_BLINKING      Colour = 25
+	NOT_REVERSED      Colour = 27
+	REVEAL            Colour = 28
+	NOT_STRIKETHROUGH Colour = 29
+
 	BLACK  Colour = 30
 	RED    Colour = 31
 	GREEN  Colour = 32
@@ -19,6 +38,17 @@ const (
 	PURPLE Colour = 35
 	CYAN   Colour = 36
 	WHITE  Colour = 37
+
+	EXTENDED_TEXT       Colour = 38
+	DEFAULT_TEXT        Colour = 39
+	EXTENDED_BACKGROUND Colour = 48
+	DEFAULT_BACKGROUND  Colour = 49
+
+	FRAMED        Colour = 51
+	ENCIRCLED     Colour = 52
+	OVERLINE      Colour = 53
+	NOT_FRAMED    Colour = 54
+	NOT_OVERLINED Colour = 55
 )
 
 // RGBA8 returns the RGB representation of the ansi colours.
@@ -34,56 +64,27 @@ func (c Colour) RGBA8() (r, g, b, a uint32) {
 
 // RGBA8Normal returns the RGBA8 color with normal intensity.
 // Colour scheme used is Windows 10's Campbell colour scheme as described in https://en.wikipedia.org/wiki/ANSI_escape_code.
+// Use Palette.Colour to pick a different colour scheme.
 func (c Colour) RGBA8Normal() (r, g, b, a uint32) {
-	a = 255
-
-	switch c.Normalize() {
-	case BLACK:
-		return 12, 12, 12, a
-	case RED:
-		return 197, 15, 31, a
-	case GREEN:
-		return 19, 161, 14, a
-	case YELLOW:
-		return 193, 156, 0, a
-	case BLUE:
-		return 0, 55, 218, a
-	case PURPLE:
-		return 136, 23, 152, a
-	case CYAN:
-		return 58, 150, 221, a
-	case WHITE:
-		return 204, 204, 204, a
-	default:
-		return
+	if c.IsHighIntensity() {
+		c -= HighIntensityOffset
 	}
+	return rgba8(Campbell.Colour(c))
 }
 
 // RGBA8Bright returns the RGBA color with high intensity.
 // Colour scheme used is Windows 10's Campbell colour scheme as described in https://en.wikipedia.org/wiki/ANSI_escape_code.
+// Use Palette.Colour to pick a different colour scheme.
 func (c Colour) RGBA8Bright() (r, g, b, a uint32) {
-	a = 255
-
-	switch c.Normalize() {
-	case BLACK:
-		return 118, 118, 118, a
-	case RED:
-		return 231, 72, 86, a
-	case GREEN:
-		return 22, 198, 12, a
-	case YELLOW:
-		return 249, 241, 165, a
-	case BLUE:
-		return 59, 120, 255, a
-	case PURPLE:
-		return 180, 0, 158, a
-	case CYAN:
-		return 97, 214, 214, a
-	case WHITE:
-		return 242, 242, 242, a
-	default:
-		return
+	if !c.IsHighIntensity() {
+		c += HighIntensityOffset
 	}
+	return rgba8(Campbell.Colour(c))
+}
+
+// rgba8 returns the components of a color.RGBA without scaling them.
+func rgba8(c color.RGBA) (r, g, b, a uint32) {
+	return uint32(c.R), uint32(c.G), uint32(c.B), uint32(c.A)
 }
 
 // RGBA implements the color.Color interface.
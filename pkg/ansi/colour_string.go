@@ -1,13 +1,64 @@
 package ansi
 
 func (c Colour) String() (res string) {
-	switch c.Normalize() {
+	switch c {
 	case NORMAL:
 		return "NORMAL"
 	case BOLD:
 		return "BOLD"
+	case FAINT:
+		return "FAINT"
 	case ITALIC:
 		return "ITALIC"
+	case UNDERLINE:
+		return "UNDERLINE"
+	case SLOW_BLINK:
+		return "SLOW_BLINK"
+	case RAPID_BLINK:
+		return "RAPID_BLINK"
+	case REVERSE:
+		return "REVERSE"
+	case CONCEAL:
+		return "CONCEAL"
+	case STRIKETHROUGH:
+		return "STRIKETHROUGH"
+	case DOUBLE_UNDERLINE:
+		return "DOUBLE_UNDERLINE"
+	case NORMAL_INTENSITY:
+		return "NORMAL_INTENSITY"
+	case NOT_ITALIC:
+		return "NOT_ITALIC"
+	case NOT_UNDERLINED:
+		return "NOT_UNDERLINED"
+	case NOT_BLINKING:
+		return "NOT_BLINKING"
+	case NOT_REVERSED:
+		return "NOT_REVERSED"
+	case REVEAL:
+		return "REVEAL"
+	case NOT_STRIKETHROUGH:
+		return "NOT_STRIKETHROUGH"
+	case EXTENDED_TEXT:
+		return "EXTENDED_TEXT"
+	case DEFAULT_TEXT:
+		return "DEFAULT_TEXT"
+	case EXTENDED_BACKGROUND:
+		return "EXTENDED_BACKGROUND"
+	case DEFAULT_BACKGROUND:
+		return "DEFAULT_BACKGROUND"
+	case FRAMED:
+		return "FRAMED"
+	case ENCIRCLED:
+		return "ENCIRCLED"
+	case OVERLINE:
+		return "OVERLINE"
+	case NOT_FRAMED:
+		return "NOT_FRAMED"
+	case NOT_OVERLINED:
+		return "NOT_OVERLINED"
+	}
+
+	switch c.Normalize() {
 	case BLACK:
 		res = "BLACK"
 	case RED:
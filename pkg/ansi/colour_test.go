@@ -79,3 +79,23 @@ func TestColour_IsHighIntensity(t *testing.T) {
 		}
 	}
 }
+
+func TestColour_String(t *testing.T) {
+	var tests = map[Colour]string{
+		FAINT:                      "FAINT",
+		ITALIC:                     "ITALIC",
+		NOT_OVERLINED:              "NOT_OVERLINED",
+		FRAMED:                     "FRAMED",
+		RED:                        "RED",
+		RED + BackgroundOffset:     "RED_BACKGROUND",
+		CYAN + HighIntensityOffset: "CYAN_BRIGHT",
+		EXTENDED_BACKGROUND:        "EXTENDED_BACKGROUND",
+		WHITE + BackgroundOffset + HighIntensityOffset: "WHITE_BACKGROUND_BRIGHT",
+	}
+
+	for c, expected := range tests {
+		if c.String() != expected {
+			t.Fatalf("Colour %d should be %s, got %s", c, expected, c.String())
+		}
+	}
+}
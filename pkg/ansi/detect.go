@@ -0,0 +1,144 @@
+package ansi
+
+import (
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ciColours are the colour levels of CI providers known to support colours, by the variable they set.
+var ciColours = []struct {
+	variable string
+	level    Level
+}{
+	{"GITHUB_ACTIONS", LevelTrueColour},
+	{"GITEA_ACTIONS", LevelTrueColour},
+	{"GITLAB_CI", Level16},
+	{"BUILDKITE", Level16},
+	{"CIRCLECI", Level16},
+	{"TRAVIS", Level16},
+	{"APPVEYOR", Level16},
+	{"DRONE", Level16},
+	{"TEAMCITY_VERSION", Level16},
+	{"TF_BUILD", Level16},
+}
+
+// termPrograms are the colour levels of terminals that identify themselves with TERM_PROGRAM.
+var termPrograms = map[string]Level{
+	"iTerm.app":      LevelTrueColour,
+	"vscode":         LevelTrueColour,
+	"WezTerm":        LevelTrueColour,
+	"ghostty":        LevelTrueColour,
+	"Hyper":          LevelTrueColour,
+	"Apple_Terminal": Level256,
+}
+
+// basicTerms are the TERM prefixes of terminals with at least 16 colours.
+var basicTerms = []string{"xterm", "screen", "tmux", "vt100", "vt220", "rxvt", "linux", "cygwin", "ansi", "konsole", "putty"}
+
+// DetectLevel returns the colour level of the terminal described by the environment variables in env.
+//
+// FORCE_COLOR sets the level, from 0 or false for none to 3 or more for true colour,
+// with any other value, such as an empty one or true, meaning 16 colours.
+// CLICOLOR_FORCE forces at least 16 colours.
+// Unless forced, NO_COLOR and CLICOLOR=0 disable colours.
+// Otherwise, the level is detected from COLORTERM, TERM_PROGRAM, the CI provider and TERM,
+// and any forced level is a minimum.
+// For terminals the environment doesn't describe, see ansi.LoadTerminfo and Terminfo.Level.
+func DetectLevel(env map[string]string) Level {
+	var forced = LevelNone
+	if value, ok := env["FORCE_COLOR"]; ok {
+		var number, err = strconv.Atoi(value)
+		switch {
+		case value == "0" || value == "false":
+			return LevelNone
+		case err == nil && number >= 3:
+			forced = LevelTrueColour
+		case err == nil && number == 2:
+			forced = Level256
+		default:
+			forced = Level16
+		}
+	} else if value := env["CLICOLOR_FORCE"]; value != "" && value != "0" {
+		forced = Level16
+	}
+
+	if forced == LevelNone && (env["NO_COLOR"] != "" || env["CLICOLOR"] == "0") {
+		return LevelNone
+	}
+
+	return max(forced, detectLevel(env))
+}
+
+// detectLevel returns the colour level of the terminal described by env, ignoring any preferences.
+func detectLevel(env map[string]string) Level {
+	var term = strings.ToLower(env["TERM"])
+	if term == "dumb" {
+		return LevelNone
+	}
+
+	switch strings.ToLower(env["COLORTERM"]) {
+	case "truecolor", "24bit":
+		return LevelTrueColour
+	}
+
+	if _, ok := env["WT_SESSION"]; ok {
+		return LevelTrueColour
+	}
+
+	if level, ok := termPrograms[env["TERM_PROGRAM"]]; ok {
+		return level
+	}
+
+	if _, ok := env["CI"]; ok {
+		for _, ci := range ciColours {
+			if _, ok := env[ci.variable]; ok {
+				return ci.level
+			}
+		}
+		return LevelNone
+	}
+
+	switch {
+	case strings.HasSuffix(term, "-direct"), strings.Contains(term, "truecolor"), strings.Contains(term, "24bit"):
+		return LevelTrueColour
+	case strings.Contains(term, "256"):
+		return Level256
+	case strings.Contains(term, "color"):
+		return Level16
+	}
+
+	for _, prefix := range basicTerms {
+		if strings.HasPrefix(term, prefix) {
+			return Level16
+		}
+	}
+
+	if env["COLORTERM"] != "" {
+		return Level16
+	}
+
+	return LevelNone
+}
+
+// EnvironmentLevel returns the colour level of the terminal described by the environment of the process.
+// See ansi.DetectLevel for how it is detected.
+func EnvironmentLevel() Level {
+	return DetectLevel(environment())
+}
+
+// NewEnvironmentWriter creates a DowngradeWriter that writes to w for the colour level of the environment of the process.
+func NewEnvironmentWriter(w io.Writer) *DowngradeWriter {
+	return NewDowngradeWriter(w, EnvironmentLevel())
+}
+
+// environment returns the environment variables of the process as a map.
+func environment() map[string]string {
+	var env = make(map[string]string)
+	for _, variable := range os.Environ() {
+		var name, value, _ = strings.Cut(variable, "=")
+		env[name] = value
+	}
+	return env
+}
@@ -0,0 +1,81 @@
+package ansi
+
+import (
+	"image/color"
+	"testing"
+)
+
+func TestDetectLevel(t *testing.T) {
+	var tests = []struct {
+		name string
+		env  map[string]string
+		want Level
+	}{
+		{name: "Empty environment", env: map[string]string{}, want: LevelNone},
+		{name: "Dumb terminal", env: map[string]string{"TERM": "dumb"}, want: LevelNone},
+		{name: "Basic terminal", env: map[string]string{"TERM": "xterm"}, want: Level16},
+		{name: "Linux console", env: map[string]string{"TERM": "linux"}, want: Level16},
+		{name: "Colour terminal", env: map[string]string{"TERM": "foot-color"}, want: Level16},
+		{name: "256 colour terminal", env: map[string]string{"TERM": "screen-256color"}, want: Level256},
+		{name: "Direct colour terminal", env: map[string]string{"TERM": "xterm-direct"}, want: LevelTrueColour},
+		{name: "COLORTERM", env: map[string]string{"TERM": "xterm-256color", "COLORTERM": "truecolor"}, want: LevelTrueColour},
+		{name: "Any COLORTERM", env: map[string]string{"COLORTERM": "yes"}, want: Level16},
+		{name: "Windows Terminal", env: map[string]string{"WT_SESSION": "0b5f1ad2"}, want: LevelTrueColour},
+		{name: "Terminal.app", env: map[string]string{"TERM": "xterm-256color", "TERM_PROGRAM": "Apple_Terminal"}, want: Level256},
+		{name: "iTerm2", env: map[string]string{"TERM": "xterm-256color", "TERM_PROGRAM": "iTerm.app"}, want: LevelTrueColour},
+		{name: "GitHub Actions", env: map[string]string{"CI": "true", "GITHUB_ACTIONS": "true"}, want: LevelTrueColour},
+		{name: "GitLab CI", env: map[string]string{"CI": "true", "GITLAB_CI": "true", "TERM": "xterm-256color"}, want: Level16},
+		{name: "Unknown CI", env: map[string]string{"CI": "true", "TERM": "xterm"}, want: LevelNone},
+		{name: "NO_COLOR", env: map[string]string{"NO_COLOR": "1", "COLORTERM": "truecolor"}, want: LevelNone},
+		{name: "Empty NO_COLOR", env: map[string]string{"NO_COLOR": "", "TERM": "xterm"}, want: Level16},
+		{name: "CLICOLOR", env: map[string]string{"CLICOLOR": "0", "TERM": "xterm"}, want: LevelNone},
+		{name: "CLICOLOR_FORCE", env: map[string]string{"CLICOLOR_FORCE": "1", "NO_COLOR": "1"}, want: Level16},
+		{name: "CLICOLOR_FORCE disabled", env: map[string]string{"CLICOLOR_FORCE": "0", "TERM": "xterm-256color"}, want: Level256},
+		{name: "FORCE_COLOR", env: map[string]string{"FORCE_COLOR": "", "TERM": "dumb"}, want: Level16},
+		{name: "FORCE_COLOR level", env: map[string]string{"FORCE_COLOR": "3", "NO_COLOR": "1"}, want: LevelTrueColour},
+		{name: "FORCE_COLOR minimum", env: map[string]string{"FORCE_COLOR": "1", "COLORTERM": "24bit"}, want: LevelTrueColour},
+		{name: "FORCE_COLOR disabled", env: map[string]string{"FORCE_COLOR": "0", "COLORTERM": "truecolor"}, want: LevelNone},
+		{name: "FORCE_COLOR false", env: map[string]string{"FORCE_COLOR": "false", "TERM": "xterm"}, want: LevelNone},
+		{name: "FORCE_COLOR above 3", env: map[string]string{"FORCE_COLOR": "4", "TERM": "dumb"}, want: LevelTrueColour},
+		{name: "FORCE_COLOR yes", env: map[string]string{"FORCE_COLOR": "yes", "NO_COLOR": "1"}, want: Level16},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.name, func(t *testing.T) {
+				if got := DetectLevel(test.env); got != test.want {
+					t.Fatalf("Detected %s instead of %s", got, test.want)
+				}
+			},
+		)
+	}
+}
+
+func TestEnvironmentLevel(t *testing.T) {
+	t.Setenv("FORCE_COLOR", "2")
+	t.Setenv("TERM", "dumb")
+	if EnvironmentLevel() != Level256 {
+		t.Fatal("Level wasn't detected from the environment")
+	}
+	if NewEnvironmentWriter(nil).Level != Level256 {
+		t.Fatal("Writer doesn't use the environment level")
+	}
+}
+
+func TestPainter_Downgrade(t *testing.T) {
+	var p = NewPainter(color.RGBA{R: 255, G: 135, A: 255}, Indexed(12))
+	p.Bold = true
+
+	if got := string(p.Downgrade(Level256).Sequence()); got != "\x1b[1;38;5;208;48;5;12m" {
+		t.Fatalf("Downgraded to %q", got)
+	}
+	if got := string(p.Downgrade(Level16).Sequence()); got != "\x1b[1;33;104m" {
+		t.Fatalf("Downgraded to %q", got)
+	}
+	if got := string(p.Downgrade(LevelNone).Sequence()); got != "\x1b[1m" {
+		t.Fatalf("Downgraded to %q", got)
+	}
+	if _, ok := p.TextColor().(color.RGBA); !ok {
+		t.Fatal("Original painter was changed")
+	}
+}
@@ -0,0 +1,85 @@
+package ansi
+
+import "io"
+
+// DowngradeWriter rewrites the colour sequences written to it for a terminal with the given Level.
+// RGB colours become the nearest Indexed colour at Level256, and every colour becomes the nearest Colour at Level16.
+// At LevelNone colours are removed, and so are the other attributes unless KeepAttributes is set.
+// Text, any other sequence and everything at LevelTrueColour are written unchanged.
+//
+// Sequences split across writes are held back until they are complete, or until they reach ansi.MaxScanLength,
+// so Flush or Close must be called once done to write anything that is left.
+type DowngradeWriter struct {
+	Level Level
+	// KeepAttributes keeps attributes such as bold and underline at LevelNone.
+	KeepAttributes bool
+
+	w      io.Writer
+	tokens tokenizer
+	out    []byte
+}
+
+// NewDowngradeWriter creates a DowngradeWriter that writes to w for a terminal with the given level.
+// Use ansi.NewEnvironmentWriter to detect the level from the environment instead.
+func NewDowngradeWriter(w io.Writer, level Level) *DowngradeWriter {
+	return &DowngradeWriter{Level: level, w: w}
+}
+
+// Write rewrites the colour sequences in data and writes the result to the underlying writer.
+// Incomplete sequences at the end of data are kept until the next write.
+// Data is always consumed, even if writing to the underlying writer fails.
+func (d *DowngradeWriter) Write(data []byte) (n int, err error) {
+	d.tokens.push(data)
+	return len(data), d.write(false)
+}
+
+// Flush writes any incomplete sequence held back by the writer as is.
+func (d *DowngradeWriter) Flush() error {
+	return d.write(true)
+}
+
+// Close flushes the writer. It doesn't close the underlying writer.
+func (d *DowngradeWriter) Close() error {
+	return d.Flush()
+}
+
+// write rewrites every complete token in the pending data and writes them to the underlying writer.
+func (d *DowngradeWriter) write(atEOF bool) (err error) {
+	d.out = d.out[:0]
+	for token := d.tokens.next(atEOF); token != nil; token = d.tokens.next(atEOF) {
+		d.out = d.rewrite(d.out, token)
+	}
+
+	if len(d.out) > 0 {
+		_, err = d.w.Write(d.out)
+	}
+	return
+}
+
+// rewrite appends the token to dst, converting it for the level if it is a colour sequence.
+func (d *DowngradeWriter) rewrite(dst []byte, token []byte) []byte {
+	if d.Level >= LevelTrueColour || Classify(token) != KindSGR {
+		return append(dst, token...)
+	}
+
+	var buffer [MaxParameters]Code
+	var codes, err = Lenient.appendCodes(buffer[:0], token)
+	if err != nil {
+		return append(dst, token...)
+	}
+
+	// Sequences without any valid code don't change colours
+	if len(codes) == 0 {
+		if d.Level == LevelNone && !d.KeepAttributes {
+			return dst
+		}
+		return append(dst, token...)
+	}
+
+	var converted [MaxParameters]Code
+	var res = d.Level.appendCodes(converted[:0], codes, d.KeepAttributes)
+	if len(res) == 0 {
+		return dst
+	}
+	return AppendCodes(dst, res...)
+}
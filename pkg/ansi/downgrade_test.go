@@ -0,0 +1,171 @@
+package ansi
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// downgrade writes the chunks through a DowngradeWriter for the level and returns the output.
+func downgrade(t *testing.T, level Level, keepAttributes bool, chunks ...string) string {
+	var out bytes.Buffer
+	var w = NewDowngradeWriter(&out, level)
+	w.KeepAttributes = keepAttributes
+
+	for _, chunk := range chunks {
+		if n, err := w.Write([]byte(chunk)); err != nil || n != len(chunk) {
+			t.Fatal("Failed to write", n, err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal("Failed to close", err)
+	}
+
+	return out.String()
+}
+
+func TestDowngradeWriter(t *testing.T) {
+	const input = "\x1b[1;38;2;255;135;0mwarning\x1b[0m: \x1b[48:5:9mdisk\x1b[49m \x1b[2Kfull\x1b]0;title\x07"
+
+	var tests = []struct {
+		name           string
+		level          Level
+		keepAttributes bool
+		want           string
+	}{
+		{
+			name:  "True colour",
+			level: LevelTrueColour,
+			want:  input,
+		},
+		{
+			name:  "256 colours",
+			level: Level256,
+			want:  "\x1b[1;38;5;208mwarning\x1b[0m: \x1b[48;5;9mdisk\x1b[49m \x1b[2Kfull\x1b]0;title\x07",
+		},
+		{
+			name:  "16 colours",
+			level: Level16,
+			want:  "\x1b[1;33mwarning\x1b[0m: \x1b[101mdisk\x1b[49m \x1b[2Kfull\x1b]0;title\x07",
+		},
+		{
+			name:  "No colours",
+			level: LevelNone,
+			want:  "warning: disk \x1b[2Kfull\x1b]0;title\x07",
+		},
+		{
+			name:           "No colours with attributes",
+			level:          LevelNone,
+			keepAttributes: true,
+			want:           "\x1b[1mwarning\x1b[0m: disk \x1b[2Kfull\x1b]0;title\x07",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.name, func(t *testing.T) {
+				if got := downgrade(t, test.level, test.keepAttributes, input); got != test.want {
+					t.Fatalf("Downgraded to %q instead of %q", got, test.want)
+				}
+			},
+		)
+	}
+
+	t.Run(
+		"Split writes", func(t *testing.T) {
+			var chunks []string
+			for i := range input {
+				chunks = append(chunks, input[i:i+1])
+			}
+			if got := downgrade(t, Level16, false, chunks...); got != tests[2].want {
+				t.Fatalf("Downgraded split writes to %q", got)
+			}
+		},
+	)
+
+	t.Run(
+		"Incomplete sequence", func(t *testing.T) {
+			var out bytes.Buffer
+			var w = NewDowngradeWriter(&out, LevelNone)
+			w.Write([]byte("text\x1b[31"))
+			if out.String() != "text" {
+				t.Fatal("Incomplete sequence wasn't held back", out.String())
+			}
+			w.Flush()
+			if out.String() != "text\x1b[31" {
+				t.Fatal("Incomplete sequence wasn't flushed", out.String())
+			}
+		},
+	)
+
+	t.Run(
+		"Empty reset", func(t *testing.T) {
+			if got := downgrade(t, Level16, false, "\x1b[mtext"); got != "\x1b[0mtext" {
+				t.Fatal("Empty reset wasn't kept as a reset", got)
+			}
+			if got := downgrade(t, LevelNone, false, "\x1b[mtext"); got != "text" {
+				t.Fatal("Empty reset wasn't removed", got)
+			}
+		},
+	)
+
+	t.Run(
+		"Invalid codes", func(t *testing.T) {
+			if got := downgrade(t, Level16, false, "\x1b[999mtext"); got != "\x1b[999mtext" {
+				t.Fatal("Sequence without valid codes was changed", got)
+			}
+			if got := downgrade(t, LevelNone, false, "\x1b[999mtext"); got != "text" {
+				t.Fatal("Sequence without valid codes wasn't removed", got)
+			}
+		},
+	)
+
+	t.Run(
+		"Unterminated control string", func(t *testing.T) {
+			var out bytes.Buffer
+			var w = NewDowngradeWriter(&out, Level16)
+			var line = strings.Repeat("x", 899) + "\n"
+
+			w.Write([]byte("log\x1b]0;oops"))
+			for i := 0; i < 1000; i++ {
+				w.Write([]byte(line))
+			}
+			w.Write([]byte("\x1b[38;2;255;0;0mred"))
+
+			if out.Len() < 1000*len(line)-MaxScanLength {
+				t.Fatalf("Stray introducer held back the output, only %d bytes were written", out.Len())
+			}
+
+			w.Close()
+			var want = "log\x1b]0;oops" + strings.Repeat(line, 1000) + "\x1b[31mred"
+			if out.String() != want {
+				t.Fatal("Text after a stray introducer wasn't written unchanged")
+			}
+		},
+	)
+
+	t.Run(
+		"Write error", func(t *testing.T) {
+			var w = NewDowngradeWriter(failingWriter{}, Level16)
+			if n, err := w.Write([]byte("text\x1b[3")); err == nil || n != 7 {
+				t.Fatal("Write error wasn't returned with the consumed data", n, err)
+			}
+
+			// The consumed data isn't written again
+			var out bytes.Buffer
+			w.w = &out
+			w.Write([]byte("1mred"))
+			if out.String() != "\x1b[31mred" {
+				t.Fatalf("Wrote %q after the error", out.String())
+			}
+		},
+	)
+}
+
+// failingWriter is a writer that always fails.
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("failed")
+}
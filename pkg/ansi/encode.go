@@ -0,0 +1,273 @@
+package ansi
+
+import (
+	"image/color"
+	"strconv"
+)
+
+// AppendCodes appends the ANSI sequence for the given codes to dst and returns the extended slice.
+// Extended colour codes without a colour are skipped, and an empty list of codes is written as a NORMAL code.
+func AppendCodes(dst []byte, codes ...Code) []byte {
+	dst = append(dst, EscapeCode, StartCode)
+
+	var written int
+	for _, code := range codes {
+		if (code.Colour == EXTENDED_TEXT || code.Colour == EXTENDED_BACKGROUND) && code.Extended == nil {
+			continue
+		}
+
+		if written > 0 {
+			dst = append(dst, Separator)
+		}
+		dst = appendCode(dst, code)
+		written++
+	}
+
+	if written == 0 {
+		dst = strconv.AppendInt(dst, int64(NORMAL), 10)
+	}
+
+	return append(dst, EndCode)
+}
+
+// Codes returns the codes that turn a default painter into this one.
+func (p *Painter) Codes() (codes []Code) {
+	var flags = []struct {
+		set  bool
+		code Colour
+	}{
+		{p.Bold, BOLD},
+		{p.Faint, FAINT},
+		{p.Italic, ITALIC},
+		{p.SlowBlink, SLOW_BLINK},
+		{p.RapidBlink, RAPID_BLINK},
+		{p.Reverse, REVERSE},
+		{p.Conceal, CONCEAL},
+		{p.Strikethrough, STRIKETHROUGH},
+		{p.Framed, FRAMED},
+		{p.Encircled, ENCIRCLED},
+		{p.Overline, OVERLINE},
+	}
+
+	for _, flag := range flags {
+		if flag.set {
+			codes = append(codes, Code{Colour: flag.code})
+		}
+	}
+
+	if p.Underline != NoUnderline {
+		codes = append(codes, underlineCode(p.Underline))
+	}
+
+	if p.text != nil {
+		codes = append(codes, colourCode(p.text, false))
+	}
+
+	if p.back != nil {
+		codes = append(codes, colourCode(p.back, true))
+	}
+
+	return
+}
+
+// Sequence returns the shortest ANSI sequence that turns a default painter into this one,
+// followed by the OSC 8 sequence that opens its link, if any.
+func (p *Painter) Sequence() []byte {
+	var res = AppendCodes(nil, p.Codes()...)
+	if p.Link != nil {
+		res = append(res, p.Link.Sequence()...)
+	}
+	return res
+}
+
+// Transition returns the shortest ANSI sequence that turns the painter from into the painter to.
+// If the links differ, the OSC 8 sequence that switches them follows.
+// Nil painters are treated as default painters.
+// Returns nil if both painters are equivalent.
+func Transition(from, to *Painter) (res []byte) {
+	if from == nil {
+		from = DefaultPainter()
+	}
+
+	if to == nil {
+		to = DefaultPainter()
+	}
+
+	res = transitionStyle(from, to)
+	if !from.Link.Equal(to.Link) {
+		res = append(res, to.Link.Sequence()...)
+	}
+
+	return
+}
+
+// transitionStyle returns the shortest ANSI sequence that turns the style of the painter from into the painter to.
+func transitionStyle(from, to *Painter) []byte {
+	var delta = transitionCodes(from, to)
+	if len(delta) == 0 {
+		return nil
+	}
+
+	var (
+		incremental = AppendCodes(nil, delta...)
+		reset       = AppendCodes(nil, append([]Code{{Colour: NORMAL}}, to.Codes()...)...)
+	)
+
+	if len(reset) < len(incremental) {
+		return reset
+	}
+
+	return incremental
+}
+
+// transitionCodes returns the codes that turn the painter from into the painter to, without resetting.
+func transitionCodes(from, to *Painter) (codes []Code) {
+	// Attributes that share a single code to turn them off
+	var groups = []struct {
+		from, to []bool
+		set      []Colour
+		off      Colour
+	}{
+		{[]bool{from.Bold, from.Faint}, []bool{to.Bold, to.Faint}, []Colour{BOLD, FAINT}, NORMAL_INTENSITY},
+		{[]bool{from.Italic}, []bool{to.Italic}, []Colour{ITALIC}, NOT_ITALIC},
+		{[]bool{from.SlowBlink, from.RapidBlink}, []bool{to.SlowBlink, to.RapidBlink}, []Colour{SLOW_BLINK, RAPID_BLINK}, NOT_BLINKING},
+		{[]bool{from.Reverse}, []bool{to.Reverse}, []Colour{REVERSE}, NOT_REVERSED},
+		{[]bool{from.Conceal}, []bool{to.Conceal}, []Colour{CONCEAL}, REVEAL},
+		{[]bool{from.Strikethrough}, []bool{to.Strikethrough}, []Colour{STRIKETHROUGH}, NOT_STRIKETHROUGH},
+		{[]bool{from.Framed, from.Encircled}, []bool{to.Framed, to.Encircled}, []Colour{FRAMED, ENCIRCLED}, NOT_FRAMED},
+		{[]bool{from.Overline}, []bool{to.Overline}, []Colour{OVERLINE}, NOT_OVERLINED},
+	}
+
+	for _, group := range groups {
+		var cleared bool
+		for i := range group.from {
+			cleared = cleared || (group.from[i] && !group.to[i])
+		}
+
+		if cleared {
+			codes = append(codes, Code{Colour: group.off})
+		}
+
+		for i := range group.to {
+			if group.to[i] && (cleared || !group.from[i]) {
+				codes = append(codes, Code{Colour: group.set[i]})
+			}
+		}
+	}
+
+	if from.Underline != to.Underline {
+		codes = append(codes, underlineCode(to.Underline))
+	}
+
+	if !sameColour(from.text, to.text) {
+		if to.text == nil {
+			codes = append(codes, Code{Colour: DEFAULT_TEXT})
+		} else {
+			codes = append(codes, colourCode(to.text, false))
+		}
+	}
+
+	if !sameColour(from.back, to.back) {
+		if to.back == nil {
+			codes = append(codes, Code{Colour: DEFAULT_BACKGROUND})
+		} else {
+			codes = append(codes, colourCode(to.back, true))
+		}
+	}
+
+	return
+}
+
+// underlineCode returns the shortest code that sets the given underline style.
+func underlineCode(style UnderlineStyle) Code {
+	switch style {
+	case NoUnderline:
+		return Code{Colour: NOT_UNDERLINED}
+	case DoubleUnderline:
+		return Code{Colour: DOUBLE_UNDERLINE, Underline: style}
+	default:
+		return Code{Colour: UNDERLINE, Underline: style}
+	}
+}
+
+// sameColour checks if a and b are the same colour.
+// Colours of this package are compared by value, and any other colour by its RGBA values,
+// since not every implementation of color.Color can be compared with ==.
+func sameColour(a, b color.Color) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+
+	switch a := a.(type) {
+	case Colour:
+		var c, ok = b.(Colour)
+		return ok && a == c
+	case Indexed:
+		var c, ok = b.(Indexed)
+		return ok && a == c
+	case color.RGBA:
+		var c, ok = b.(color.RGBA)
+		return ok && a == c
+	}
+
+	var r1, g1, b1, a1 = a.RGBA()
+	var r2, g2, b2, a2 = b.RGBA()
+	return r1 == r2 && g1 == g2 && b1 == b2 && a1 == a2
+}
+
+// colourCode returns the code that sets c as the text or background colour.
+// Colour values that aren't colours, such as BOLD, are written as their RGB value.
+func colourCode(c color.Color, background bool) Code {
+	var extended = Code{Colour: EXTENDED_TEXT}
+	if background {
+		extended.Colour = EXTENDED_BACKGROUND
+	}
+
+	switch c := c.(type) {
+	case Colour:
+		switch {
+		case background && c.IsText():
+			return Code{Colour: c + BackgroundOffset}
+		case !background && c.IsBackground():
+			return Code{Colour: c - BackgroundOffset}
+		case c.IsText() || c.IsBackground():
+			return Code{Colour: c}
+		}
+	case Indexed, color.RGBA:
+		extended.Extended = c
+		return extended
+	}
+
+	var n = color.NRGBAModel.Convert(c).(color.NRGBA)
+	extended.Extended = color.RGBA{R: n.R, G: n.G, B: n.B, A: 255}
+	return extended
+}
+
+// appendCode appends the parameters of a single code to dst.
+func appendCode(dst []byte, code Code) []byte {
+	dst = strconv.AppendInt(dst, int64(code.Colour), 10)
+
+	switch code.Colour {
+	case UNDERLINE:
+		if code.Underline != SingleUnderline {
+			dst = append(dst, SubSeparator)
+			dst = strconv.AppendInt(dst, int64(code.Underline), 10)
+		}
+	case EXTENDED_TEXT, EXTENDED_BACKGROUND:
+		switch c := code.Extended.(type) {
+		case Indexed:
+			dst = append(dst, Separator, '0'+ExtendedIndexed, Separator)
+			dst = strconv.AppendInt(dst, int64(c), 10)
+		default:
+			var r, g, b, _ = c.RGBA()
+			dst = append(dst, Separator, '0'+ExtendedRGB, Separator)
+			dst = strconv.AppendInt(dst, int64(r>>8), 10)
+			dst = append(dst, Separator)
+			dst = strconv.AppendInt(dst, int64(g>>8), 10)
+			dst = append(dst, Separator)
+			dst = strconv.AppendInt(dst, int64(b>>8), 10)
+		}
+	}
+
+	return dst
+}
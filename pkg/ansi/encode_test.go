@@ -0,0 +1,118 @@
+package ansi
+
+import (
+	"image/color"
+	"testing"
+)
+
+func Test_AppendCodes(t *testing.T) {
+	var tests = []struct {
+		codes    []Code
+		expected string
+	}{
+		{codes: nil, expected: "\x1b[0m"},
+		{codes: []Code{{Colour: BOLD}, {Colour: RED}}, expected: "\x1b[1;31m"},
+		{codes: []Code{{Colour: UNDERLINE, Underline: CurlyUnderline}}, expected: "\x1b[4:3m"},
+		{codes: []Code{{Colour: UNDERLINE, Underline: SingleUnderline}}, expected: "\x1b[4m"},
+		{codes: []Code{{Colour: EXTENDED_TEXT, Extended: Indexed(208)}}, expected: "\x1b[38;5;208m"},
+		{
+			codes:    []Code{{Colour: EXTENDED_BACKGROUND, Extended: color.RGBA{R: 255, G: 128, A: 255}}},
+			expected: "\x1b[48;2;255;128;0m",
+		},
+		{codes: []Code{{Colour: BOLD}, {Colour: EXTENDED_TEXT}}, expected: "\x1b[1m"},
+		{codes: []Code{{Colour: EXTENDED_BACKGROUND}}, expected: "\x1b[0m"},
+	}
+
+	for _, test := range tests {
+		var actual = string(AppendCodes(nil, test.codes...))
+		if actual != test.expected {
+			t.Fatalf("Expected %q, got %q", test.expected, actual)
+		}
+	}
+}
+
+func TestPainter_Sequence(t *testing.T) {
+	var sequences = []string{
+		"\x1b[0m",
+		"\x1b[1;3;31m",
+		"\x1b[2;4:3;38;5;208;48;2;1;2;3m",
+		"\x1b[1;5;7;8;9;51;53;21;97;104m",
+	}
+
+	for _, sequence := range sequences {
+		var p = NewPainterFromSequence([]byte(sequence))
+		if actual := string(p.Sequence()); actual != sequence {
+			t.Fatalf("Expected %q, got %q", sequence, actual)
+		}
+	}
+
+	var p = NewPainter(color.Gray{Y: 128}, BLUE)
+	if actual := string(p.Sequence()); actual != "\x1b[38;2;128;128;128;44m" {
+		t.Fatalf("Arbitrary colours aren't being converted, got %q", actual)
+	}
+
+	p = NewPainter(BOLD, Colour(200))
+	if actual := string(p.Sequence()); actual != "\x1b[38;2;0;0;0;48;2;0;0;0m" {
+		t.Fatalf("Codes that aren't colours should be written as RGB, got %q", actual)
+	}
+}
+
+// sliceColour is a color.Color that can't be compared with ==.
+type sliceColour []uint8
+
+func (c sliceColour) RGBA() (r, g, b, a uint32) {
+	return color.RGBA{R: c[0], G: c[1], B: c[2], A: 255}.RGBA()
+}
+
+func Test_Transition(t *testing.T) {
+	var tests = []struct {
+		name     string
+		from, to string
+		expected string
+	}{
+		{name: "Same", from: "\x1b[1;31m", to: "\x1b[31;1m", expected: ""},
+		{name: "Add", from: "\x1b[1m", to: "\x1b[1;31m", expected: "\x1b[31m"},
+		{name: "Default colour", from: "\x1b[1;31;42m", to: "\x1b[1;42m", expected: "\x1b[39m"},
+		{name: "Shared off code", from: "\x1b[1;2;31m", to: "\x1b[2;31m", expected: "\x1b[22;2m"},
+		{name: "Underline style", from: "\x1b[4m", to: "\x1b[4:3m", expected: "\x1b[4:3m"},
+		{name: "Reset", from: "\x1b[1;3;4;9;31;42m", to: "\x1b[0m", expected: "\x1b[0m"},
+		{name: "Reset and apply", from: "\x1b[1;3;4;9;31;42m", to: "\x1b[35m", expected: "\x1b[0;35m"},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.name, func(t *testing.T) {
+				var (
+					from   = NewPainterFromSequence([]byte(test.from))
+					to     = NewPainterFromSequence([]byte(test.to))
+					actual = Transition(from, to)
+				)
+
+				if string(actual) != test.expected {
+					t.Fatalf("Expected %q, got %q", test.expected, actual)
+				}
+
+				if actual != nil {
+					_ = from.Apply(actual)
+				}
+
+				if *from != *to {
+					t.Fatal("Transition doesn't produce the target painter")
+				}
+			},
+		)
+	}
+
+	if Transition(nil, nil) != nil {
+		t.Fatal("Nil painters should be equivalent")
+	}
+
+	t.Run(
+		"Incomparable colours", func(t *testing.T) {
+			var from, to = NewPainter(sliceColour{1, 2, 3}, nil), NewPainter(sliceColour{1, 2, 3}, sliceColour{4, 5, 6})
+			if actual := string(Transition(from, to)); actual != "\x1b[48;2;4;5;6m" {
+				t.Fatalf("Expected only the background to change, got %q", actual)
+			}
+		},
+	)
+}
@@ -0,0 +1,101 @@
+package ansi
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNotSequence indicates that the input doesn't start with a control sequence introducer.
+var ErrNotSequence = errors.New("not a control sequence")
+
+// ErrUnterminated indicates that a control sequence doesn't end with a final byte.
+var ErrUnterminated = errors.New("unterminated sequence")
+
+// ErrUnknownFinal indicates that a control sequence ends with a final byte that isn't supported.
+var ErrUnknownFinal = errors.New("unknown final byte")
+
+// ErrorCategory is the kind of problem reported by a ParseError.
+type ErrorCategory byte
+
+const (
+	// CategoryMalformed is used when the input isn't a control sequence at all.
+	CategoryMalformed ErrorCategory = iota
+	// CategoryOverflow is used when a value is too large.
+	CategoryOverflow
+	// CategoryNonNumeric is used when a parameter is empty or not a number.
+	CategoryNonNumeric
+	// CategoryUnterminated is used when a sequence is missing its final byte.
+	CategoryUnterminated
+	// CategoryUnknownFinal is used when a sequence has an unsupported final byte.
+	CategoryUnknownFinal
+	// CategoryInvalid is used when a code is missing parameters or has parameters that don't apply to it.
+	CategoryInvalid
+)
+
+func (c ErrorCategory) String() string {
+	switch c {
+	case CategoryMalformed:
+		return "malformed"
+	case CategoryOverflow:
+		return "overflow"
+	case CategoryNonNumeric:
+		return "non-numeric"
+	case CategoryUnterminated:
+		return "unterminated"
+	case CategoryUnknownFinal:
+		return "unknown final byte"
+	case CategoryInvalid:
+		return "invalid"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseError describes a malformed sequence and where the problem was found.
+// It wraps the error that caused it, so it can be checked with errors.Is as well as errors.As.
+type ParseError struct {
+	// Sequence is a copy of the raw sequence that failed to parse.
+	Sequence []byte
+	// Offset is the position of the offending byte in Sequence, or the start of the offending parameter
+	// if the whole parameter is at fault, such as when its value is too large.
+	// Unterminated sequences are reported at their last byte.
+	Offset int
+	// Index is the position of the offending parameter, counting sub-parameters, or -1 if no parameter is at fault.
+	Index    int
+	Category ErrorCategory
+	Err      error
+}
+
+// newParseError creates a ParseError with a copy of the sequence.
+func newParseError(sequence []byte, offset, index int, category ErrorCategory, err error) *ParseError {
+	return &ParseError{
+		Sequence: append([]byte(nil), sequence...),
+		Offset:   offset,
+		Index:    index,
+		Category: category,
+		Err:      err,
+	}
+}
+
+func (e *ParseError) Error() string {
+	if e.Index < 0 {
+		return fmt.Sprintf("ansi: %s at byte %d of %q: %v", e.Category, e.Offset, e.Sequence, e.Err)
+	}
+	return fmt.Sprintf("ansi: %s parameter %d at byte %d of %q: %v", e.Category, e.Index, e.Offset, e.Sequence, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// parameterOffset returns the position in sequence of the parameter at the given index, counting sub-parameters.
+// The 8-bit CSI introducer is only accepted if c1 is set.
+func parameterOffset(sequence []byte, index int, c1 bool) (offset int) {
+	var _, start = sequenceStart(sequence, c1)
+	for offset = start; index > 0 && offset < len(sequence); offset++ {
+		if sequence[offset] == Separator || sequence[offset] == SubSeparator {
+			index--
+		}
+	}
+	return
+}
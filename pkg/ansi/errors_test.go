@@ -0,0 +1,82 @@
+package ansi
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseError(t *testing.T) {
+	var tests = []struct {
+		sequence string
+		offset   int
+		index    int
+		category ErrorCategory
+		err      error
+	}{
+		{sequence: "Hello", offset: 0, index: -1, category: CategoryMalformed, err: ErrNotSequence},
+		{sequence: "\x1b]0;title\x07", offset: 0, index: -1, category: CategoryMalformed, err: ErrNotSequence},
+		{sequence: "\x1b[31", offset: 3, index: -1, category: CategoryUnterminated, err: ErrUnterminated},
+		{sequence: "\x1b[", offset: 1, index: -1, category: CategoryUnterminated, err: ErrUnterminated},
+		{sequence: "\x1b[2K", offset: 3, index: -1, category: CategoryUnknownFinal, err: ErrUnknownFinal},
+		{sequence: "\x1b[1;am", offset: 4, index: 1, category: CategoryNonNumeric, err: ErrInvalidParameter},
+		{sequence: "\x1b[1;;3m", offset: 4, index: 1, category: CategoryNonNumeric, err: ErrInvalidParameter},
+		{sequence: "\x1b[1;99999m", offset: 4, index: 1, category: CategoryOverflow, err: ErrParameterOverflow},
+		{sequence: "\x1b[1;300m", offset: 4, index: 1, category: CategoryOverflow, err: ErrByteOverflow},
+		{sequence: "\x1b[1;38;5;300m", offset: 9, index: 3, category: CategoryOverflow, err: ErrByteOverflow},
+		{sequence: "\x1b[1;38:2:1m", offset: 4, index: 1, category: CategoryInvalid, err: ErrInvalidExtended},
+		{sequence: "\x1b[38;5;300m", offset: 7, index: 2, category: CategoryOverflow, err: ErrByteOverflow},
+		{sequence: "\x1b[48:2::1:999:3m", offset: 10, index: 4, category: CategoryOverflow, err: ErrByteOverflow},
+		{sequence: "\x1b[38;7;1m", offset: 5, index: 1, category: CategoryInvalid, err: ErrInvalidExtended},
+		{sequence: "\x1b[38;2;1m", offset: 2, index: 0, category: CategoryInvalid, err: ErrInvalidExtended},
+		{sequence: "\x1b[0;4:7m", offset: 6, index: 2, category: CategoryInvalid, err: ErrInvalidUnderline},
+	}
+
+	for _, test := range tests {
+		var _, err = ParseCodes([]byte(test.sequence))
+
+		var parseErr *ParseError
+		if !errors.As(err, &parseErr) {
+			t.Fatalf("Sequence %q should fail with a ParseError, got %v", test.sequence, err)
+		}
+
+		if parseErr.Offset != test.offset || parseErr.Index != test.index || parseErr.Category != test.category {
+			t.Fatalf(
+				"Sequence %q should fail at byte %d, parameter %d with %s, got %d, %d, %s",
+				test.sequence, test.offset, test.index, test.category,
+				parseErr.Offset, parseErr.Index, parseErr.Category,
+			)
+		}
+
+		if !errors.Is(err, test.err) {
+			t.Fatalf("Sequence %q should wrap %v, got %v", test.sequence, test.err, parseErr.Err)
+		}
+
+		if string(parseErr.Sequence) != test.sequence {
+			t.Fatal("ParseError should hold the raw sequence")
+		}
+	}
+}
+
+func TestParseError_Error(t *testing.T) {
+	var _, err = ParseCodes([]byte("\x1b[1;am"))
+	if err.Error() != `ansi: non-numeric parameter 1 at byte 4 of "\x1b[1;am": invalid parameter` {
+		t.Fatalf("Unexpected error message %q", err.Error())
+	}
+
+	_, err = ParseCodes([]byte("\x1b[31"))
+	if err.Error() != `ansi: unterminated at byte 3 of "\x1b[31": unterminated sequence` {
+		t.Fatalf("Unexpected error message %q", err.Error())
+	}
+}
+
+func TestParsePainter(t *testing.T) {
+	var p, err = ParsePainter([]byte("\x1b[1;31m"))
+	if err != nil || !p.Bold || p.TextColor() != RED {
+		t.Fatal("Failed to parse valid sequence")
+	}
+
+	p, err = ParsePainter([]byte("\x1b[1;38;5m"))
+	if p != nil || err == nil {
+		t.Fatal("Invalid sequence should error instead of panicking")
+	}
+}
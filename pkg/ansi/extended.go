@@ -0,0 +1,47 @@
+package ansi
+
+// Indexed is a colour from the xterm 256 colour palette, as selected by the 38;5;n and 48;5;n sequences.
+// The first 16 entries are the system colours, followed by a 6x6x6 colour cube and a 24-step greyscale ramp.
+type Indexed byte
+
+const (
+	// ExtendedRGB selects a 24-bit colour after an EXTENDED_TEXT or EXTENDED_BACKGROUND code.
+	ExtendedRGB = 2
+	// ExtendedIndexed selects an Indexed colour after an EXTENDED_TEXT or EXTENDED_BACKGROUND code.
+	ExtendedIndexed = 5
+)
+
+const (
+	cubeOffset = 16
+	greyOffset = 232
+)
+
+// cubeLevels are the intensities of each step of the 6x6x6 colour cube.
+var cubeLevels = [6]uint32{0, 95, 135, 175, 215, 255}
+
+// RGBA8 returns the RGB representation of the indexed colour using the xterm palette.
+// Use Palette.Resolve to pick a different colour scheme for the first 16 colours.
+func (i Indexed) RGBA8() (r, g, b, a uint32) {
+	a = 255
+
+	switch {
+	case i < cubeOffset:
+		return rgba8(XTerm.Colours[i])
+	case i < greyOffset:
+		var n = uint32(i - cubeOffset)
+		return cubeLevels[n/36], cubeLevels[(n/6)%6], cubeLevels[n%6], a
+	default:
+		var grey = 8 + 10*uint32(i-greyOffset)
+		return grey, grey, grey, a
+	}
+}
+
+// RGBA implements the color.Color interface.
+func (i Indexed) RGBA() (r, g, b, a uint32) {
+	r, g, b, a = i.RGBA8()
+	r |= r << 8
+	g |= g << 8
+	b |= b << 8
+	a |= a << 8
+	return
+}
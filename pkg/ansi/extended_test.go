@@ -0,0 +1,33 @@
+package ansi
+
+import "testing"
+
+func TestIndexed_RGBA8(t *testing.T) {
+	var tests = []struct {
+		index   Indexed
+		r, g, b uint32
+	}{
+		{index: 1, r: 205, g: 0, b: 0},
+		{index: 15, r: 255, g: 255, b: 255},
+		{index: 16, r: 0, g: 0, b: 0},
+		{index: 196, r: 255, g: 0, b: 0},
+		{index: 208, r: 255, g: 135, b: 0},
+		{index: 231, r: 255, g: 255, b: 255},
+		{index: 232, r: 8, g: 8, b: 8},
+		{index: 255, r: 238, g: 238, b: 238},
+	}
+
+	for _, test := range tests {
+		var r, g, b, a = test.index.RGBA8()
+		if r != test.r || g != test.g || b != test.b || a != 255 {
+			t.Fatalf("Indexed colour %d resolved to %d,%d,%d,%d", test.index, r, g, b, a)
+		}
+	}
+}
+
+func TestIndexed_RGBA(t *testing.T) {
+	var r, g, b, a = Indexed(208).RGBA()
+	if r != 0xFFFF || g != 0x8787 || b != 0 || a != 0xFFFF {
+		t.Fatal("Indexed colour isn't scaled to 16 bits")
+	}
+}
@@ -0,0 +1,366 @@
+package ansi
+
+import (
+	"fmt"
+	"image/color"
+	"io"
+	"net/url"
+	"strings"
+)
+
+// htmlDecorations are the text decoration lines, in the order they are written, with their classes.
+var htmlDecorations = []struct {
+	class, line string
+}{
+	{"ansi-underline", "underline"},
+	{"ansi-strikethrough", "line-through"},
+	{"ansi-overline", "overline"},
+}
+
+// htmlUnderlines are the text decoration styles of each underline style, indexed by UnderlineStyle.
+var htmlUnderlines = [...]string{SingleUnderline: "solid", DoubleUnderline: "double", CurlyUnderline: "wavy", DottedUnderline: "dotted", DashedUnderline: "dashed"}
+
+// htmlLinkSchemes are the URI schemes of the hyperlinks that are kept as links.
+var htmlLinkSchemes = []string{"http", "https", "mailto"}
+
+// HTMLRenderer converts text with ANSI sequences into HTML, with a span for every run of text that shares a style.
+// Text is HTML escaped, control characters other than tabs and line feeds are removed,
+// and OSC 8 hyperlinks become links if they are http, https or mailto URIs.
+// Blinking isn't rendered.
+type HTMLRenderer struct {
+	// Palette resolves colours into RGB, and defaults to Campbell if nil.
+	Palette *Palette
+	// Classes selects the CSS classes written by Stylesheet for the base colours and attributes instead of inline styles.
+	// Indexed colours past the first 16 and RGB colours always use inline styles.
+	Classes bool
+}
+
+// htmlRun is the state of a renderer between tokens.
+type htmlRun struct {
+	painter     Painter
+	open, close string
+}
+
+// Render converts the text with ANSI sequences read from src into HTML and writes it to w.
+// The output isn't wrapped in any element, so it is usually written inside a pre element.
+// Sequences split across reads are held back until they are complete, or until they reach ansi.MaxScanLength.
+func (r *HTMLRenderer) Render(w io.Writer, src io.Reader) (err error) {
+	var run htmlRun
+	var tokens tokenizer
+	var buf = make([]byte, 32*1024)
+	var out []byte
+
+	for err == nil {
+		var n int
+		n, err = src.Read(buf)
+		tokens.push(buf[:n])
+
+		var atEOF = err != nil
+		out = r.appendTokens(out[:0], &run, &tokens, atEOF)
+
+		if atEOF {
+			out = append(out, run.close...)
+		}
+		if _, werr := w.Write(out); werr != nil {
+			return werr
+		}
+	}
+
+	if err == io.EOF {
+		return nil
+	}
+	return err
+}
+
+// RenderString converts the text with ANSI sequences in s into HTML.
+func (r *HTMLRenderer) RenderString(s string) string {
+	var run htmlRun
+	var tokens tokenizer
+	tokens.push([]byte(s))
+
+	var out = r.appendTokens(nil, &run, &tokens, true)
+	return string(append(out, run.close...))
+}
+
+// Stylesheet returns the CSS rules of the classes used when Classes is set, with the colours of the palette.
+// The ansi class sets the default colours, for the element that contains the output.
+func (r *HTMLRenderer) Stylesheet() string {
+	var p = r.palette()
+	var b strings.Builder
+
+	fmt.Fprintf(&b, ".ansi { color: %s; background-color: %s; }\n", htmlColour(p.Foreground), htmlColour(p.Background))
+	for i, c := range p.Colours {
+		fmt.Fprintf(&b, ".%s { color: %s; }\n", htmlColourClass(i, false), htmlColour(c))
+	}
+	for i, c := range p.Colours {
+		fmt.Fprintf(&b, ".%s { background-color: %s; }\n", htmlColourClass(i, true), htmlColour(c))
+	}
+
+	b.WriteString(".ansi-bold { font-weight: bold; }\n")
+	b.WriteString(".ansi-faint { opacity: 0.5; }\n")
+	b.WriteString(".ansi-italic { font-style: italic; }\n")
+
+	// Every combination of decoration lines, since each class would override the others
+	for set := 1; set < 1<<len(htmlDecorations); set++ {
+		var selector, lines strings.Builder
+		for i, decoration := range htmlDecorations {
+			if set&(1<<i) != 0 {
+				selector.WriteString("." + decoration.class)
+				if lines.Len() > 0 {
+					lines.WriteByte(' ')
+				}
+				lines.WriteString(decoration.line)
+			}
+		}
+		fmt.Fprintf(&b, "%s { text-decoration-line: %s; }\n", selector.String(), lines.String())
+	}
+	for style := DoubleUnderline; style <= DashedUnderline; style++ {
+		fmt.Fprintf(&b, ".ansi-underline-%s { text-decoration-style: %s; }\n", underlineName(style), htmlUnderlines[style])
+	}
+
+	b.WriteString(".ansi-conceal { visibility: hidden; }\n")
+	b.WriteString(".ansi-framed { border: 1px solid; }\n")
+	b.WriteString(".ansi-encircled { border: 1px solid; border-radius: 0.5em; }\n")
+
+	return b.String()
+}
+
+// palette returns the palette of the renderer, or Campbell if there isn't one.
+func (r *HTMLRenderer) palette() *Palette {
+	if r.Palette == nil {
+		return &Campbell
+	}
+	return r.Palette
+}
+
+// appendTokens appends the HTML of every complete token in the stream to dst.
+func (r *HTMLRenderer) appendTokens(dst []byte, run *htmlRun, tokens *tokenizer, atEOF bool) []byte {
+	for token := tokens.next(atEOF); token != nil; token = tokens.next(atEOF) {
+		var n int
+		dst, n = r.appendToken(dst, run, token, atEOF && tokens.empty())
+		if n < len(token) {
+			tokens.unread(len(token) - n)
+			break
+		}
+	}
+	return dst
+}
+
+// appendToken appends the HTML of a single token to dst.
+// Sequences update the style, and text is written in a span for the style, reusing the open span if it is the same.
+// If atEOF is set, token is the end of the stream, so an unterminated control string only loses its introducer.
+// Returns how much of token was used, which is less than all of it if it ends with an incomplete escape sequence
+// and atEOF isn't set.
+func (r *HTMLRenderer) appendToken(dst []byte, run *htmlRun, token []byte, atEOF bool) ([]byte, int) {
+	if Classify(token) != KindText {
+		_ = Lenient.Apply(&run.painter, token)
+		return dst, len(token)
+	}
+
+	var text, n = stripText(nil, token, atEOF)
+	if !htmlVisible(text) {
+		return dst, n
+	}
+
+	if open, close := r.tags(&run.painter); open != run.open {
+		dst = append(dst, run.close...)
+		dst = append(dst, open...)
+		run.open, run.close = open, close
+	}
+
+	return appendHTMLText(dst, text), n
+}
+
+// tags returns the opening and closing tags for text with the style of the painter, which are empty for default text.
+func (r *HTMLRenderer) tags(p *Painter) (open, close string) {
+	var classes, styles []string
+	var palette = r.palette()
+
+	var text, back = p.TextColor(), p.BackgroundColor()
+	if p.Reverse {
+		text, back = back, text
+		if text == nil {
+			text = palette.Background
+		}
+		if back == nil {
+			back = palette.Foreground
+		}
+	}
+
+	var colour = func(c color.Color, background bool) {
+		if c == nil {
+			return
+		}
+		if index, ok := baseIndex(c); ok && r.Classes {
+			classes = append(classes, htmlColourClass(index, background))
+			return
+		}
+
+		var property = "color"
+		if background {
+			property = "background-color"
+		}
+		styles = append(styles, property+":"+htmlColour(palette.Resolve(c)))
+	}
+	colour(text, false)
+	colour(back, true)
+
+	var attribute = func(set bool, class, style string) {
+		switch {
+		case !set:
+		case r.Classes:
+			classes = append(classes, class)
+		default:
+			styles = append(styles, style)
+		}
+	}
+	attribute(p.Bold, "ansi-bold", "font-weight:bold")
+	attribute(p.Faint, "ansi-faint", "opacity:0.5")
+	attribute(p.Italic, "ansi-italic", "font-style:italic")
+
+	var lines []string
+	for i, set := range []bool{p.Underline != NoUnderline, p.Strikethrough, p.Overline} {
+		if set {
+			lines = append(lines, htmlDecorations[i].line)
+			if r.Classes {
+				classes = append(classes, htmlDecorations[i].class)
+			}
+		}
+	}
+	if len(lines) > 0 && !r.Classes {
+		styles = append(styles, "text-decoration-line:"+strings.Join(lines, " "))
+	}
+	if p.Underline > SingleUnderline && int(p.Underline) < len(htmlUnderlines) {
+		attribute(true, "ansi-underline-"+underlineName(p.Underline), "text-decoration-style:"+htmlUnderlines[p.Underline])
+	}
+
+	attribute(p.Conceal, "ansi-conceal", "visibility:hidden")
+	attribute(p.Framed && !p.Encircled, "ansi-framed", "border:1px solid")
+	attribute(p.Encircled, "ansi-encircled", "border:1px solid;border-radius:0.5em")
+
+	if len(classes) > 0 || len(styles) > 0 {
+		open = "<span"
+		if len(classes) > 0 {
+			open += ` class="` + strings.Join(classes, " ") + `"`
+		}
+		if len(styles) > 0 {
+			open += ` style="` + strings.Join(styles, ";") + `"`
+		}
+		open += ">"
+		close = "</span>"
+	}
+
+	if href, ok := htmlLink(p.Link); ok {
+		open = `<a href="` + href + `">` + open
+		close += "</a>"
+	}
+
+	return
+}
+
+// baseIndex returns the position of a base colour in a palette, if c is one.
+func baseIndex(c color.Color) (index int, ok bool) {
+	switch c := c.(type) {
+	case Colour:
+		if !c.IsText() && !c.IsBackground() {
+			return 0, false
+		}
+		index = int(c.Normalize() - BLACK)
+		if c.IsHighIntensity() {
+			index += 8
+		}
+		return index, true
+	case Indexed:
+		return int(c), c < 16
+	default:
+		return 0, false
+	}
+}
+
+// htmlColourClass returns the class of the base colour at index in a palette, such as ansi-red or ansi-bg-green-bright.
+func htmlColourClass(index int, background bool) string {
+	var class = "ansi-"
+	if background {
+		class += "bg-"
+	}
+	class += strings.ToLower((BLACK + Colour(index%8)).String())
+	if index >= 8 {
+		class += "-bright"
+	}
+	return class
+}
+
+// htmlColour returns the CSS hex notation of a colour.
+func htmlColour(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+// htmlLink returns the escaped URI of a hyperlink, if it is one that can be followed safely.
+func htmlLink(link *Hyperlink) (string, bool) {
+	if link == nil {
+		return "", false
+	}
+
+	var uri, err = url.Parse(link.URI)
+	if err != nil {
+		return "", false
+	}
+
+	for _, scheme := range htmlLinkSchemes {
+		if strings.EqualFold(uri.Scheme, scheme) {
+			return string(appendHTMLText(nil, []byte(link.URI))), true
+		}
+	}
+	return "", false
+}
+
+// appendHTMLText appends text to dst, escaping the HTML special characters and removing control characters
+// other than tabs and line feeds.
+func appendHTMLText(dst, text []byte) []byte {
+	for _, b := range text {
+		switch {
+		case b == '<':
+			dst = append(dst, "&lt;"...)
+		case b == '>':
+			dst = append(dst, "&gt;"...)
+		case b == '&':
+			dst = append(dst, "&amp;"...)
+		case b == '"':
+			dst = append(dst, "&#34;"...)
+		case b == '\'':
+			dst = append(dst, "&#39;"...)
+		case b == '\t' || b == '\n':
+			dst = append(dst, b)
+		case b < 0x20 || b == deleteCode:
+		default:
+			dst = append(dst, b)
+		}
+	}
+	return dst
+}
+
+// htmlVisible checks if text has anything left once control characters are removed.
+func htmlVisible(text []byte) bool {
+	for _, b := range text {
+		if b == '\t' || b == '\n' || (b >= 0x20 && b != deleteCode) {
+			return true
+		}
+	}
+	return false
+}
+
+// underlineName returns the lower case name of an underline style, as used in class names.
+func underlineName(style UnderlineStyle) string {
+	switch style {
+	case DoubleUnderline:
+		return "double"
+	case CurlyUnderline:
+		return "curly"
+	case DottedUnderline:
+		return "dotted"
+	case DashedUnderline:
+		return "dashed"
+	default:
+		return "single"
+	}
+}
@@ -0,0 +1,221 @@
+package ansi
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestHTMLRenderer_RenderString(t *testing.T) {
+	var tests = []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "Plain text",
+			input: "plain text\n",
+			want:  "plain text\n",
+		},
+		{
+			name:  "Escaping",
+			input: "<b>\"Tom\" & 'Jerry'</b>\a",
+			want:  "&lt;b&gt;&#34;Tom&#34; &amp; &#39;Jerry&#39;&lt;/b&gt;",
+		},
+		{
+			name:  "Colours",
+			input: "\x1b[31mred\x1b[0m \x1b[1;97;44mbright\x1b[m",
+			want:  `<span style="color:#c50f1f">red</span> <span style="color:#f2f2f2;background-color:#0037da;font-weight:bold">bright</span>`,
+		},
+		{
+			name:  "Extended colours",
+			input: "\x1b[38;5;208;48;2;1;2;3morange",
+			want:  `<span style="color:#ff8700;background-color:#010203">orange</span>`,
+		},
+		{
+			name:  "Merged styles",
+			input: "\x1b[32mgr\x1b[0m\x1b[32mee\x1b[1m\x1b[22mn\x1b[0m",
+			want:  `<span style="color:#13a10e">green</span>`,
+		},
+		{
+			name:  "Attributes",
+			input: "\x1b[2;3;4:3;9;53;8mall",
+			want:  `<span style="opacity:0.5;font-style:italic;text-decoration-line:underline line-through overline;text-decoration-style:wavy;visibility:hidden">all</span>`,
+		},
+		{
+			name:  "Reverse",
+			input: "\x1b[7;31mreversed",
+			want:  `<span style="color:#0c0c0c;background-color:#c50f1f">reversed</span>`,
+		},
+		{
+			name:  "Hyperlink",
+			input: "\x1b]8;;https://example.com/?a=1&b=2\x1b\\\x1b[1mlink\x1b]8;;\x1b\\ text",
+			want:  `<a href="https://example.com/?a=1&amp;b=2"><span style="font-weight:bold">link</span></a><span style="font-weight:bold"> text</span>`,
+		},
+		{
+			name:  "Unsafe hyperlink",
+			input: "\x1b]8;;javascript:alert(1)\x1b\\link\x1b]8;;\x1b\\",
+			want:  "link",
+		},
+		{
+			name:  "Unterminated control string",
+			input: "a\x1b]0;x\nline2\n<line3>",
+			want:  "a0;x\nline2\n&lt;line3&gt;",
+		},
+		{
+			name:  "Other sequences",
+			input: "\x1b[2J\x1b7\x1b[31mred\x1b[Kline\x1b]0;title\x07",
+			want:  `<span style="color:#c50f1f">redline</span>`,
+		},
+	}
+
+	var r HTMLRenderer
+	for _, test := range tests {
+		t.Run(
+			test.name, func(t *testing.T) {
+				if got := r.RenderString(test.input); got != test.want {
+					t.Fatalf("Rendered\n%s\ninstead of\n%s", got, test.want)
+				}
+			},
+		)
+	}
+}
+
+func TestHTMLRenderer_Classes(t *testing.T) {
+	var r = HTMLRenderer{Classes: true}
+
+	t.Run(
+		"Base colours", func(t *testing.T) {
+			var got = r.RenderString("\x1b[1;31;102mtext\x1b[38;5;4;48;5;208mmore")
+			var want = `<span class="ansi-red ansi-bg-green-bright ansi-bold">text</span>` +
+				`<span class="ansi-blue ansi-bold" style="background-color:#ff8700">more</span>`
+			if got != want {
+				t.Fatalf("Rendered %s", got)
+			}
+		},
+	)
+
+	t.Run(
+		"Attributes", func(t *testing.T) {
+			var got = r.RenderString("\x1b[21;9;51mtext")
+			var want = `<span class="ansi-underline ansi-strikethrough ansi-underline-double ansi-framed">text</span>`
+			if got != want {
+				t.Fatalf("Rendered %s", got)
+			}
+		},
+	)
+
+	t.Run(
+		"Stylesheet", func(t *testing.T) {
+			var css = (&HTMLRenderer{Palette: &Dracula}).Stylesheet()
+			for _, rule := range []string{
+				".ansi { color: #f8f8f2; background-color: #282a36; }",
+				".ansi-red { color: #ff5555; }",
+				".ansi-bg-green-bright { background-color: #69ff94; }",
+				".ansi-bold { font-weight: bold; }",
+				".ansi-underline.ansi-strikethrough { text-decoration-line: underline line-through; }",
+				".ansi-underline-curly { text-decoration-style: wavy; }",
+			} {
+				if !strings.Contains(css, rule) {
+					t.Fatalf("Stylesheet is missing %q", rule)
+				}
+			}
+		},
+	)
+}
+
+func TestHTMLRenderer_Render(t *testing.T) {
+	var r = HTMLRenderer{Palette: &VGA}
+	const input = "\x1b[31mred\x1b[0m and \x1b[32mgreen\x1b[0m\n"
+
+	var out bytes.Buffer
+	if err := r.Render(&out, iotest.OneByteReader(strings.NewReader(input))); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != r.RenderString(input) {
+		t.Fatalf("Rendered %q from a reader", out.String())
+	}
+	if !strings.Contains(out.String(), "color:#aa0000") {
+		t.Fatal("Palette wasn't used")
+	}
+
+	t.Run(
+		"Split reads", func(t *testing.T) {
+			var input = "a\x1b(Bb \x1b[1;31mbuild \xf0\x9f\x98\x80 <done>\x1b[0m\x1b]8;;https://a\x1b\\ěĝ\x1b]8;;\x07\x1b7 end\n"
+			var want = r.RenderString(input)
+
+			for name, reader := range map[string]io.Reader{
+				"OneByte": iotest.OneByteReader(strings.NewReader(input)),
+				"Half":    iotest.HalfReader(strings.NewReader(input)),
+			} {
+				var out bytes.Buffer
+				if err := r.Render(&out, reader); err != nil || out.String() != want {
+					t.Fatalf("%s: rendered %q instead of %q", name, out.String(), want)
+				}
+			}
+
+			for i := 0; i <= len(input); i++ {
+				var out bytes.Buffer
+				var reader = io.MultiReader(strings.NewReader(input[:i]), strings.NewReader(input[i:]))
+				if err := r.Render(&out, reader); err != nil || out.String() != want {
+					t.Fatalf("Split at %d rendered %q instead of %q", i, out.String(), want)
+				}
+			}
+		},
+	)
+
+	t.Run(
+		"Unterminated control string", func(t *testing.T) {
+			var out bytes.Buffer
+			var line = strings.Repeat("x", 899) + "\n"
+			var reader = &chunkReader{chunks: []string{"a\x1b]0;x\n"}}
+			for i := 0; i < 1000; i++ {
+				reader.chunks = append(reader.chunks, line)
+			}
+			reader.check = func(read int) {
+				if read > 100 && out.Len() < (read-100)*len(line) {
+					t.Fatalf("Stray introducer held back the output, only %d bytes were written", out.Len())
+				}
+			}
+
+			if err := r.Render(&out, reader); err != nil || out.String() != "a0;x\n"+strings.Repeat(line, 1000) {
+				t.Fatal("Text after a stray introducer was lost", err)
+			}
+		},
+	)
+
+	t.Run(
+		"Read error", func(t *testing.T) {
+			if err := r.Render(&out, iotest.ErrReader(iotest.ErrTimeout)); err != iotest.ErrTimeout {
+				t.Fatal("Read error wasn't returned", err)
+			}
+		},
+	)
+
+	t.Run(
+		"Write error", func(t *testing.T) {
+			if err := r.Render(failingWriter{}, strings.NewReader(input)); err == nil || errors.Is(err, iotest.ErrTimeout) {
+				t.Fatal("Write error wasn't returned", err)
+			}
+		},
+	)
+}
+
+// chunkReader returns one of its chunks on every read, calling check with the number of chunks read so far.
+type chunkReader struct {
+	chunks []string
+	read   int
+	check  func(read int)
+}
+
+func (c *chunkReader) Read(p []byte) (int, error) {
+	if c.read == len(c.chunks) {
+		return 0, io.EOF
+	}
+	c.check(c.read)
+	c.read++
+	return copy(p, c.chunks[c.read-1]), nil
+}
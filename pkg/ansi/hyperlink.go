@@ -0,0 +1,149 @@
+package ansi
+
+import (
+	"bytes"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// HyperlinkCode is the OSC command number that opens and closes hyperlinks.
+const HyperlinkCode = "8"
+
+// Hyperlink is a clickable link, as opened by an OSC 8 sequence.
+// Text between the opening sequence and the closing sequence (an OSC 8 with an empty URI) belongs to the link.
+// Links with the same ID and URI are treated as a single link by terminals, even if they are split by other text.
+type Hyperlink struct {
+	URI string
+	ID  string
+}
+
+// ParseHyperlink parses an OSC 8 sequence, as returned by ansi.ScanCodes.
+// Percent-encoded bytes in the ID are decoded, the same as they are encoded by Hyperlink.Sequence.
+// Returns a nil link for the sequence that closes a link, and false if the token isn't an OSC 8 sequence.
+func ParseHyperlink(token []byte) (link *Hyperlink, ok bool) {
+	return parseHyperlink(token, false)
+}
+
+// parseHyperlink implements ansi.ParseHyperlink, with C1 introducers if c1 is set.
+func parseHyperlink(token []byte, c1 bool) (link *Hyperlink, ok bool) {
+	if classify(token, c1) != KindOSC {
+		return
+	}
+
+	var fields = bytes.SplitN(payload(token, c1), []byte{Separator}, 3)
+	if len(fields) != 3 || string(fields[0]) != HyperlinkCode {
+		return
+	}
+
+	if len(fields[2]) == 0 {
+		return nil, true
+	}
+
+	link = &Hyperlink{URI: string(fields[2])}
+	for _, param := range strings.Split(string(fields[1]), string(SubSeparator)) {
+		if id, found := strings.CutPrefix(param, "id="); found {
+			link.ID = unescapeHyperlinkID(id)
+		}
+	}
+
+	return link, true
+}
+
+// Sequence returns the OSC 8 sequence that opens the link, or closes any link if it's nil.
+// Bytes that can't be part of the sequence are percent-encoded in the URI, and so are separators and percent signs
+// in the ID, so that neither of them can end the sequence early.
+func (h *Hyperlink) Sequence() []byte {
+	var res = []byte{EscapeCode, OSCCode}
+	res = append(res, HyperlinkCode...)
+	res = append(res, Separator)
+
+	if h != nil {
+		if h.ID != "" {
+			res = append(res, "id="...)
+			res = appendHyperlinkID(res, h.ID)
+		}
+		res = append(res, Separator)
+		res = appendHyperlinkURI(res, h.URI)
+	} else {
+		res = append(res, Separator)
+	}
+
+	return append(res, EscapeCode, StringTerminatorCode)
+}
+
+// appendHyperlinkURI appends uri to dst, percent-encoding the bytes outside the printable ASCII range.
+func appendHyperlinkURI(dst []byte, uri string) []byte {
+	for i := 0; i < len(uri); i++ {
+		if isHyperlinkByte(uri[i]) {
+			dst = append(dst, uri[i])
+		} else {
+			dst = appendPercent(dst, uri[i])
+		}
+	}
+	return dst
+}
+
+// appendHyperlinkID appends id to dst, percent-encoding the bytes outside the printable ASCII range,
+// separators and percent signs.
+func appendHyperlinkID(dst []byte, id string) []byte {
+	for i := 0; i < len(id); i++ {
+		var b = id[i]
+		if isHyperlinkByte(b) && b != Separator && b != SubSeparator && b != '%' {
+			dst = append(dst, b)
+		} else {
+			dst = appendPercent(dst, b)
+		}
+	}
+	return dst
+}
+
+// unescapeHyperlinkID decodes the percent-encoded bytes in id.
+// Percent signs that aren't followed by two hex digits are kept as is.
+func unescapeHyperlinkID(id string) string {
+	if !strings.Contains(id, "%") {
+		return id
+	}
+
+	var res = make([]byte, 0, len(id))
+	for i := 0; i < len(id); i++ {
+		if id[i] == '%' && i+2 < len(id) {
+			if b, err := strconv.ParseUint(id[i+1:i+3], 16, 8); err == nil {
+				res = append(res, byte(b))
+				i += 2
+				continue
+			}
+		}
+		res = append(res, id[i])
+	}
+	return string(res)
+}
+
+// isHyperlinkByte checks if b can be written as is in an OSC 8 sequence, which is only the printable ASCII range.
+func isHyperlinkByte(b byte) bool {
+	return b > ' ' && b < deleteCode
+}
+
+// appendPercent appends the percent-encoding of b to dst.
+func appendPercent(dst []byte, b byte) []byte {
+	const digits = "0123456789ABCDEF"
+	return append(dst, '%', digits[b>>4], digits[b&0xF])
+}
+
+// Equal checks whether both links point to the same URI with the same ID.
+// Nil links are only equal to other nil links.
+func (h *Hyperlink) Equal(other *Hyperlink) bool {
+	if h == nil || other == nil {
+		return h == other
+	}
+	return *h == *other
+}
+
+// WriteHyperlink writes text wrapped in the OSC 8 sequences that open and close the link.
+// Returns the number of bytes written, including the sequences.
+func WriteHyperlink(w io.Writer, link *Hyperlink, text []byte) (n int, err error) {
+	var res = link.Sequence()
+	res = append(res, text...)
+	res = append(res, (*Hyperlink)(nil).Sequence()...)
+	return w.Write(res)
+}
@@ -0,0 +1,178 @@
+package ansi
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func Test_ParseHyperlink(t *testing.T) {
+	t.Run(
+		"Open", func(t *testing.T) {
+			var link, ok = ParseHyperlink([]byte("\x1b]8;id=docs:x=y;https://example.com/a;b\x1b\\"))
+			if !ok || link == nil {
+				t.Fatal("Failed to detect hyperlink")
+			}
+
+			if link.URI != "https://example.com/a;b" || link.ID != "docs" {
+				t.Fatalf("Hyperlink parsed incorrectly: %+v", *link)
+			}
+
+			link, ok = ParseHyperlink([]byte("\x1b]8;;file:///tmp\x07"))
+			if !ok || link == nil || link.URI != "file:///tmp" || link.ID != "" {
+				t.Fatal("Hyperlink terminated by bell parsed incorrectly")
+			}
+		},
+	)
+
+	t.Run(
+		"Close", func(t *testing.T) {
+			var link, ok = ParseHyperlink([]byte("\x1b]8;;\x1b\\"))
+			if !ok || link != nil {
+				t.Fatal("Failed to detect closing hyperlink")
+			}
+		},
+	)
+
+	t.Run(
+		"Other", func(t *testing.T) {
+			var invalid = []string{"\x1b]0;title\x07", "\x1b]8\x07", "\x1b[8m", "8;;http://a"}
+			for _, token := range invalid {
+				if _, ok := ParseHyperlink([]byte(token)); ok {
+					t.Fatalf("Token %q isn't a hyperlink", token)
+				}
+			}
+		},
+	)
+}
+
+func TestHyperlink_Sequence(t *testing.T) {
+	var link = &Hyperlink{URI: "https://example.com", ID: "1"}
+	if string(link.Sequence()) != "\x1b]8;id=1;https://example.com\x1b\\" {
+		t.Fatal("Hyperlink with ID serialised incorrectly")
+	}
+
+	link.ID = ""
+	if string(link.Sequence()) != "\x1b]8;;https://example.com\x1b\\" {
+		t.Fatal("Hyperlink serialised incorrectly")
+	}
+
+	if string((*Hyperlink)(nil).Sequence()) != "\x1b]8;;\x1b\\" {
+		t.Fatal("Closing hyperlink serialised incorrectly")
+	}
+
+	var parsed, _ = ParseHyperlink(link.Sequence())
+	if !parsed.Equal(link) {
+		t.Fatal("Hyperlink doesn't round trip")
+	}
+
+	t.Run(
+		"Unsafe URI", func(t *testing.T) {
+			var link = &Hyperlink{URI: "https://a/\x1b\\\x1b[2J\x07;b c\x9cñ"}
+			var expected = "\x1b]8;;https://a/%1B\\%1B[2J%07;b%20c%9C%C3%B1\x1b\\"
+			if actual := string(link.Sequence()); actual != expected {
+				t.Fatalf("Expected %q, got %q", expected, actual)
+			}
+		},
+	)
+
+	t.Run(
+		"Unsafe ID", func(t *testing.T) {
+			var link = &Hyperlink{URI: "https://a", ID: "a;b:c%\x1b\\ d\x07"}
+			var sequence = link.Sequence()
+			if string(sequence) != "\x1b]8;id=a%3Bb%3Ac%25%1B\\%20d%07;https://a\x1b\\" {
+				t.Fatalf("Unsafe ID bytes weren't encoded, got %q", sequence)
+			}
+
+			if parsed, ok := ParseHyperlink(sequence); !ok || !parsed.Equal(link) {
+				t.Fatal("Hyperlink with an unsafe ID doesn't round trip")
+			}
+		},
+	)
+
+	t.Run(
+		"Round trip", func(t *testing.T) {
+			var links = []*Hyperlink{
+				{URI: "https://example.com/a;jsessionid=1", ID: "a:b"},
+				{URI: "https://example.com/a;jsessionid=1", ID: "ab"},
+				{URI: "https://example.com/%41?q=1;2", ID: "100%"},
+			}
+
+			for i, link := range links {
+				var parsed, ok = ParseHyperlink(link.Sequence())
+				if !ok || !parsed.Equal(link) {
+					t.Fatalf("Hyperlink %+v doesn't round trip, got %+v", *link, parsed)
+				}
+
+				for _, other := range links[:i] {
+					if string(other.Sequence()) == string(link.Sequence()) {
+						t.Fatalf("Hyperlinks %+v and %+v have the same sequence", *other, *link)
+					}
+				}
+			}
+		},
+	)
+}
+
+func Test_WriteHyperlink(t *testing.T) {
+	var buffer bytes.Buffer
+	var n, err = WriteHyperlink(&buffer, &Hyperlink{URI: "https://example.com"}, []byte("click"))
+	var expected = "\x1b]8;;https://example.com\x1b\\click\x1b]8;;\x1b\\"
+
+	if err != nil || n != len(expected) || buffer.String() != expected {
+		t.Fatalf("Expected %q, got %q", expected, buffer.String())
+	}
+}
+
+func TestPainter_ApplyHyperlink(t *testing.T) {
+	var (
+		p       = DefaultPainter()
+		scanner = bufio.NewScanner(strings.NewReader("\x1b]8;;https://a\x1b\\\x1b[1mone\x1b[0mtwo\x1b]8;;\x1b\\three"))
+		links   []*Hyperlink
+	)
+	scanner.Split(ScanCodes)
+
+	for scanner.Scan() {
+		var token = scanner.Bytes()
+		if Classify(token) == KindText {
+			links = append(links, p.Link)
+		} else if err := p.Apply(token); err != nil {
+			t.Fatal("Errored while applying sequence")
+		}
+	}
+
+	if len(links) != 3 {
+		t.Fatal("Scanned the wrong number of text tokens")
+	}
+
+	if links[0] == nil || links[0].URI != "https://a" || links[1] == nil || links[1].URI != "https://a" {
+		t.Fatal("Text inside the link should belong to it, even after a reset")
+	}
+
+	if links[2] != nil {
+		t.Fatal("Text after the link shouldn't belong to it")
+	}
+}
+
+func Test_TransitionHyperlink(t *testing.T) {
+	var (
+		from = DefaultPainter()
+		to   = NewPainterFromSequence([]byte("\x1b[1m"))
+	)
+	to.Link = &Hyperlink{URI: "https://a"}
+
+	if string(Transition(from, to)) != "\x1b[1m\x1b]8;;https://a\x1b\\" {
+		t.Fatal("Transition should open the link")
+	}
+
+	if string(Transition(to, from)) != "\x1b[0m\x1b]8;;\x1b\\" {
+		t.Fatal("Transition should close the link")
+	}
+
+	var same = *to
+	same.Link = &Hyperlink{URI: "https://a"}
+	if Transition(to, &same) != nil {
+		t.Fatal("Equal links shouldn't need a transition")
+	}
+}
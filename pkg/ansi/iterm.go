@@ -0,0 +1,150 @@
+package ansi
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"image/color"
+	"io"
+	"math"
+	"strconv"
+)
+
+// iTermComponents are the keys of the colour components in an .itermcolors colour, in RGB order.
+var iTermComponents = [3]string{"Red Component", "Green Component", "Blue Component"}
+
+// iTermValue is a value in an .itermcolors property list, with the line it was found on.
+type iTermValue struct {
+	Line   int
+	Real   float64
+	IsReal bool
+	Dict   map[string]iTermValue
+}
+
+// ParseITerm reads an iTerm2 .itermcolors colour scheme, a property list of colours with float components.
+// Components are taken as they are, regardless of the colour space.
+// Schemes without a Cursor Color use the foreground colour for the cursor.
+// The file doesn't name the scheme, so the palette is unnamed.
+func ParseITerm(data []byte) (p *Palette, err error) {
+	var decoder = xml.NewDecoder(bytes.NewReader(data))
+
+	var root iTermValue
+	for root.Dict == nil {
+		var token xml.Token
+		if token, err = decoder.Token(); err != nil {
+			if err == io.EOF {
+				err = &PaletteError{Key: "plist", Err: ErrMissingKey}
+			}
+			return nil, err
+		}
+		if start, ok := token.(xml.StartElement); ok && start.Name.Local == "dict" {
+			if root, err = decodeITermValue(decoder, start); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	p = &Palette{}
+	for i := range p.Colours {
+		if p.Colours[i], err = iTermColour(root, fmt.Sprintf("Ansi %d Color", i)); err != nil {
+			return nil, err
+		}
+	}
+	if p.Foreground, err = iTermColour(root, "Foreground Color"); err != nil {
+		return nil, err
+	}
+	if p.Background, err = iTermColour(root, "Background Color"); err != nil {
+		return nil, err
+	}
+
+	p.Cursor = p.Foreground
+	if _, ok := root.Dict["Cursor Color"]; ok {
+		if p.Cursor, err = iTermColour(root, "Cursor Color"); err != nil {
+			return nil, err
+		}
+	}
+
+	return
+}
+
+// LoadITerm reads an iTerm2 .itermcolors colour scheme from r.
+func LoadITerm(r io.Reader) (*Palette, error) {
+	var data, err = io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return ParseITerm(data)
+}
+
+// iTermColour reads the colour with the given key from the root dictionary.
+func iTermColour(root iTermValue, key string) (c color.RGBA, err error) {
+	var value, ok = root.Dict[key]
+	if !ok {
+		return c, &PaletteError{Key: key, Err: ErrMissingKey}
+	}
+	if value.Dict == nil {
+		return c, &PaletteError{Key: key, Line: value.Line, Err: ErrInvalidColour}
+	}
+
+	var rgb [3]uint8
+	for i, name := range iTermComponents {
+		var component, ok = value.Dict[name]
+		if !ok {
+			return c, &PaletteError{Key: key, Line: value.Line, Err: fmt.Errorf("%w: missing %s", ErrInvalidColour, name)}
+		}
+		if !component.IsReal || component.Real < 0 || component.Real > 1 {
+			return c, &PaletteError{Key: key + "." + name, Line: component.Line, Err: ErrInvalidColour}
+		}
+		rgb[i] = uint8(math.Round(component.Real * 255))
+	}
+
+	return color.RGBA{R: rgb[0], G: rgb[1], B: rgb[2], A: 255}, nil
+}
+
+// decodeITermValue reads the value that starts with the given element.
+// Only dictionaries and numbers are kept, as they are the only values used by colour schemes.
+func decodeITermValue(decoder *xml.Decoder, start xml.StartElement) (value iTermValue, err error) {
+	value.Line, _ = decoder.InputPos()
+
+	switch start.Name.Local {
+	case "dict":
+		value.Dict = make(map[string]iTermValue)
+		var key string
+		for {
+			var token xml.Token
+			if token, err = decoder.Token(); err != nil {
+				return
+			}
+
+			switch token := token.(type) {
+			case xml.StartElement:
+				if token.Name.Local == "key" {
+					if err = decoder.DecodeElement(&key, &token); err != nil {
+						return
+					}
+					continue
+				}
+				if value.Dict[key], err = decodeITermValue(decoder, token); err != nil {
+					return
+				}
+			case xml.EndElement:
+				return
+			}
+		}
+
+	case "real", "integer":
+		var text string
+		if err = decoder.DecodeElement(&text, &start); err != nil {
+			return
+		}
+		var perr error
+		if value.Real, perr = strconv.ParseFloat(text, 64); perr == nil {
+			value.IsReal = true
+		}
+
+	default:
+		err = decoder.Skip()
+	}
+
+	return
+}
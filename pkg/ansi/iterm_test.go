@@ -0,0 +1,103 @@
+package ansi
+
+import (
+	"errors"
+	"fmt"
+	"image/color"
+	"strings"
+	"testing"
+)
+
+// iTermColours writes the palette as an .itermcolors file, leaving out the colour with the skipped key.
+func iTermColours(p *Palette, skip string) string {
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+`)
+
+	var write = func(key string, c color.RGBA) {
+		if key == skip {
+			return
+		}
+		fmt.Fprintf(&b, "\t<key>%s</key>\n\t<dict>\n", key)
+		fmt.Fprintf(&b, "\t\t<key>Alpha Component</key>\n\t\t<real>1</real>\n")
+		fmt.Fprintf(&b, "\t\t<key>Blue Component</key>\n\t\t<real>%f</real>\n", float64(c.B)/255)
+		fmt.Fprintf(&b, "\t\t<key>Color Space</key>\n\t\t<string>sRGB</string>\n")
+		fmt.Fprintf(&b, "\t\t<key>Green Component</key>\n\t\t<real>%f</real>\n", float64(c.G)/255)
+		fmt.Fprintf(&b, "\t\t<key>Red Component</key>\n\t\t<real>%f</real>\n", float64(c.R)/255)
+		b.WriteString("\t</dict>\n")
+	}
+
+	for i, c := range p.Colours {
+		write(fmt.Sprintf("Ansi %d Color", i), c)
+	}
+	write("Background Color", p.Background)
+	write("Foreground Color", p.Foreground)
+	write("Cursor Color", p.Cursor)
+
+	b.WriteString("</dict>\n</plist>\n")
+	return b.String()
+}
+
+func TestParseITerm(t *testing.T) {
+	t.Run(
+		"Colour scheme", func(t *testing.T) {
+			var p, err = ParseITerm([]byte(iTermColours(&TerminalApp, "")))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if p.Colours != TerminalApp.Colours {
+				t.Fatal("Wrong palette colours", p.Colours)
+			}
+			if p.Foreground != TerminalApp.Foreground || p.Background != TerminalApp.Background || p.Cursor != TerminalApp.Cursor {
+				t.Fatal("Wrong default colours")
+			}
+		},
+	)
+
+	t.Run(
+		"Default cursor", func(t *testing.T) {
+			var p, err = ParseITerm([]byte(iTermColours(&TerminalApp, "Cursor Color")))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if p.Cursor != p.Foreground {
+				t.Fatal("Cursor didn't default to the foreground")
+			}
+		},
+	)
+
+	t.Run(
+		"Missing key", func(t *testing.T) {
+			var _, err = ParseITerm([]byte(iTermColours(&TerminalApp, "Ansi 12 Color")))
+			var paletteErr *PaletteError
+			if !errors.As(err, &paletteErr) || !errors.Is(err, ErrMissingKey) || paletteErr.Key != "Ansi 12 Color" {
+				t.Fatal("Missing key wasn't reported", err)
+			}
+		},
+	)
+
+	t.Run(
+		"Invalid component", func(t *testing.T) {
+			var scheme = strings.Replace(iTermColours(&TerminalApp, ""), "<real>0.760784</real>", "<real>red</real>", 1)
+			var _, err = ParseITerm([]byte(scheme))
+			var paletteErr *PaletteError
+			if !errors.As(err, &paletteErr) || !errors.Is(err, ErrInvalidColour) {
+				t.Fatal("Invalid component wasn't reported", err)
+			}
+			if paletteErr.Key != "Ansi 1 Color.Red Component" || paletteErr.Line != 29 {
+				t.Fatal("Error doesn't point at the invalid component", err)
+			}
+		},
+	)
+
+	t.Run(
+		"Not a property list", func(t *testing.T) {
+			if _, err := ParseITerm([]byte("<html></html>")); err == nil {
+				t.Fatal("Invalid file was accepted")
+			}
+		},
+	)
+}
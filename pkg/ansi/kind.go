@@ -0,0 +1,158 @@
+package ansi
+
+// Kind is the type of a token returned by ansi.ScanCodes.
+type Kind byte
+
+const (
+	// KindText is normal text, including malformed and incomplete sequences.
+	KindText Kind = iota
+	// KindSGR is a Select Graphic Rendition sequence, which sets colours and attributes.
+	KindSGR
+	// KindCursor is a sequence that moves, saves or restores the cursor.
+	KindCursor
+	// KindErase is a sequence that erases part of the display or line.
+	KindErase
+	// KindMode is a sequence that sets or resets a terminal mode.
+	KindMode
+	// KindCSI is any other control sequence.
+	KindCSI
+	// KindOSC is an Operating System Command string, such as window titles and hyperlinks.
+	KindOSC
+	// KindDCS is a Device Control String.
+	KindDCS
+	// KindSOS is a Start Of String control string.
+	KindSOS
+	// KindPM is a Privacy Message control string.
+	KindPM
+	// KindAPC is an Application Program Command string.
+	KindAPC
+)
+
+// Classify returns the Kind of a token, as returned by ansi.ScanCodes.
+// Only 7-bit sequences are recognised, so text that starts with a byte in the C1 range,
+// such as the rest of a UTF-8 character split across reads, is always text.
+// Tokens returned by ansi.ScanCodesC1 are classified with ansi.ClassifyC1.
+func Classify(token []byte) Kind {
+	return classify(token, false)
+}
+
+// ClassifyC1 returns the Kind of a token, as returned by ansi.ScanCodesC1.
+// Tokens that start with an 8-bit C1 introducer are classified as sequences as well.
+func ClassifyC1(token []byte) Kind {
+	return classify(token, true)
+}
+
+// classify implements ansi.Classify, with C1 introducers if c1 is set.
+func classify(token []byte, c1 bool) Kind {
+	var code, start = sequenceStart(token, c1)
+	if start == 0 {
+		return KindText
+	}
+
+	var n, complete = scanControl(token, c1)
+	if !complete || n != len(token) {
+		return KindText
+	}
+
+	switch code {
+	case OSCCode:
+		return KindOSC
+	case DCSCode:
+		return KindDCS
+	case SOSCode:
+		return KindSOS
+	case PMCode:
+		return KindPM
+	case APCCode:
+		return KindAPC
+	}
+
+	var (
+		final         = token[n-1]
+		params        = token[start : n-1]
+		private       bool
+		intermediates bool
+	)
+
+	for _, b := range params {
+		private = private || (isParameterByte(b) && b >= '<')
+		intermediates = intermediates || isIntermediateByte(b)
+	}
+
+	if intermediates {
+		return KindCSI
+	}
+
+	switch final {
+	case EndCode:
+		if !private {
+			return KindSGR
+		}
+	case 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'a', 'd', 'e', 'f', '`', 's', 'u':
+		return KindCursor
+	case 'J', 'K', 'X':
+		return KindErase
+	case 'h', 'l':
+		return KindMode
+	}
+
+	return KindCSI
+}
+
+// Payload returns the contents of a token, as returned by ansi.ScanCodes.
+// Control strings return the data between the introducer and the terminator,
+// control sequences return their parameter and intermediate bytes, and text is returned as is.
+func Payload(token []byte) []byte {
+	return payload(token, false)
+}
+
+// PayloadC1 returns the contents of a token, as returned by ansi.ScanCodesC1, the same as ansi.Payload.
+func PayloadC1(token []byte) []byte {
+	return payload(token, true)
+}
+
+// payload implements ansi.Payload, with C1 introducers if c1 is set.
+func payload(token []byte, c1 bool) []byte {
+	var _, start = sequenceStart(token, c1)
+
+	switch classify(token, c1) {
+	case KindText:
+		return token
+	case KindOSC, KindDCS, KindSOS, KindPM, KindAPC:
+		if token[len(token)-1] == StringTerminatorCode && token[len(token)-2] == EscapeCode {
+			return token[start : len(token)-2]
+		}
+		return token[start : len(token)-1]
+	default:
+		return token[start : len(token)-1]
+	}
+}
+
+func (k Kind) String() string {
+	switch k {
+	case KindText:
+		return "TEXT"
+	case KindSGR:
+		return "SGR"
+	case KindCursor:
+		return "CURSOR"
+	case KindErase:
+		return "ERASE"
+	case KindMode:
+		return "MODE"
+	case KindCSI:
+		return "CSI"
+	case KindOSC:
+		return "OSC"
+	case KindDCS:
+		return "DCS"
+	case KindSOS:
+		return "SOS"
+	case KindPM:
+		return "PM"
+	case KindAPC:
+		return "APC"
+	default:
+		return "UNKNOWN"
+	}
+}
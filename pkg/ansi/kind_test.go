@@ -0,0 +1,105 @@
+package ansi
+
+import "testing"
+
+func Test_Classify(t *testing.T) {
+	var tests = map[string]Kind{
+		"Hello":                   KindText,
+		"\x1b[31":                 KindText,
+		"\x1b[31m":                KindSGR,
+		"\x1b[38:2::1:2:3m":       KindSGR,
+		"\x1b[?1m":                KindCSI,
+		"\x1b[>4;2m":              KindCSI,
+		"\x1b[10;5H":              KindCursor,
+		"\x1b[A":                  KindCursor,
+		"\x1b[s":                  KindCursor,
+		"\x1b[2K":                 KindErase,
+		"\x1b[J":                  KindErase,
+		"\x1b[?25l":               KindMode,
+		"\x1b[4h":                 KindMode,
+		"\x1b[2 q":                KindCSI,
+		"\x1b[5S":                 KindCSI,
+		"\x1b]0;title\x07":        KindOSC,
+		"\x1b]8;;http://a\x1b\\":  KindOSC,
+		"\x1bP+q544e\x1b\\":       KindDCS,
+		"\x1bXsos\x07":            KindSOS,
+		"\x1b^pm\x1b\\":           KindPM,
+		"\x1b_apc\x07":            KindAPC,
+		"\x1b]0;title":            KindText,
+		"\x1b]0;ti\x1b[0mtle\x07": KindText,
+	}
+
+	for token, expected := range tests {
+		if actual := Classify([]byte(token)); actual != expected {
+			t.Fatalf("Token %q should be %s, got %s", token, expected, actual)
+		}
+	}
+}
+
+func Test_ClassifyC1(t *testing.T) {
+	var tests = map[string]Kind{
+		"\x9b31m":         KindSGR,
+		"\x9b2K":          KindErase,
+		"\x9d0;title\x9c": KindOSC,
+		"\x90+q\x1b\\":    KindDCS,
+		"\x9fapc\x07":     KindAPC,
+		"\x1b[31m":        KindSGR,
+		"\x9b31":          KindText,
+	}
+
+	for token, expected := range tests {
+		if actual := ClassifyC1([]byte(token)); actual != expected {
+			t.Fatalf("Token %q should be %s, got %s", token, expected, actual)
+		}
+		if actual := Classify([]byte(token)); token[0] != EscapeCode && actual != KindText {
+			t.Fatalf("Token %q should be text without C1, got %s", token, actual)
+		}
+	}
+
+	t.Run(
+		"Split UTF-8", func(t *testing.T) {
+			// ě is C4 9B, ĝ is C4 9D and 😀 is F0 9F 98 80, which contain the CSI, OSC and APC bytes
+			var input = "ě31m ĝ0;x\x07 😀apc\x07"
+			for i := range input {
+				for j := i + 1; j <= len(input); j++ {
+					if kind := Classify([]byte(input[i:j])); kind != KindText {
+						t.Fatalf("Text %q was classified as %s", input[i:j], kind)
+					}
+				}
+			}
+		},
+	)
+
+	t.Run(
+		"Painter", func(t *testing.T) {
+			var p = DefaultPainter()
+			if err := p.Apply([]byte("\x9b31m")); err != nil || p.TextColor() != nil {
+				t.Fatal("C1 sequences shouldn't be applied without C1")
+			}
+		},
+	)
+}
+
+func Test_Payload(t *testing.T) {
+	var tests = map[string]string{
+		"Hello":                            "Hello",
+		"\x1b[1;31m":                       "1;31",
+		"\x1b[2 q":                         "2 ",
+		"\x1b]0;title\x07":                 "0;title",
+		"\x1b]8;id=1;http://a\x1b\\":       "8;id=1;http://a",
+		"\x1bPtmux;\x1b\x1b]0;x\x07\x1b\\": "tmux;\x1b\x1b]0;x\x07",
+	}
+
+	for token, expected := range tests {
+		if actual := string(Payload([]byte(token))); actual != expected {
+			t.Fatalf("Token %q should have payload %q, got %q", token, expected, actual)
+		}
+	}
+
+	if actual := string(Payload([]byte("\x9b1;31m"))); actual != "\x9b1;31m" {
+		t.Fatalf("C1 sequences should be text without C1, got %q", actual)
+	}
+	if actual := string(PayloadC1([]byte("\x9d0;title\x9c"))); actual != "0;title" {
+		t.Fatalf("C1 payload should be %q, got %q", "0;title", actual)
+	}
+}
@@ -0,0 +1,90 @@
+package ansi
+
+import "image/color"
+
+// Level is the colour capability of a terminal.
+type Level byte
+
+const (
+	// LevelNone is a terminal without colours.
+	LevelNone Level = iota
+	// Level16 is a terminal with the 8 base colours and their bright versions.
+	Level16
+	// Level256 is a terminal with the xterm 256 colour palette.
+	Level256
+	// LevelTrueColour is a terminal with 24-bit RGB colours.
+	LevelTrueColour
+)
+
+// Convert returns the nearest colour to c that the level supports, or nil for LevelNone.
+// Colours that the level already supports are returned unchanged.
+func (l Level) Convert(c color.Color) color.Color {
+	switch c := c.(type) {
+	case nil:
+		return nil
+	case Colour:
+		if l == LevelNone {
+			return nil
+		}
+		return c
+	case Indexed:
+		switch {
+		case l == LevelNone:
+			return nil
+		case l >= Level256:
+			return c
+		case c < 8:
+			return BLACK + Colour(c)
+		case c < cubeOffset:
+			return BLACK + HighIntensityOffset + Colour(c-8)
+		default:
+			return NearestColour(c, nil)
+		}
+	default:
+		switch l {
+		case LevelNone:
+			return nil
+		case Level16:
+			return NearestColour(c, nil)
+		case Level256:
+			return NearestIndexed(c, nil)
+		default:
+			return c
+		}
+	}
+}
+
+// appendCodes appends the codes to dst, converted for the level.
+// Colour codes are dropped for LevelNone, as are attributes unless keepAttributes is set.
+func (l Level) appendCodes(dst []Code, codes []Code, keepAttributes bool) []Code {
+	for _, code := range codes {
+		switch {
+		case code.Colour == EXTENDED_TEXT || code.Colour == EXTENDED_BACKGROUND:
+			if c := l.Convert(code.Extended); c != nil {
+				dst = append(dst, colourCode(c, code.Colour == EXTENDED_BACKGROUND))
+			}
+		case code.Colour.IsText() || code.Colour.IsBackground() || code.Colour == DEFAULT_TEXT || code.Colour == DEFAULT_BACKGROUND:
+			if l != LevelNone {
+				dst = append(dst, code)
+			}
+		case l != LevelNone || keepAttributes:
+			dst = append(dst, code)
+		}
+	}
+	return dst
+}
+
+func (l Level) String() string {
+	switch l {
+	case LevelNone:
+		return "NONE"
+	case Level16:
+		return "16"
+	case Level256:
+		return "256"
+	case LevelTrueColour:
+		return "TRUECOLOUR"
+	default:
+		return "UNKNOWN"
+	}
+}
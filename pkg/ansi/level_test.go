@@ -0,0 +1,44 @@
+package ansi
+
+import (
+	"image/color"
+	"testing"
+)
+
+func TestLevel_Convert(t *testing.T) {
+	var orange = color.RGBA{R: 255, G: 135, B: 0, A: 255}
+	var tests = []struct {
+		name   string
+		level  Level
+		colour color.Color
+		want   color.Color
+	}{
+		{name: "True colour keeps RGB", level: LevelTrueColour, colour: orange, want: orange},
+		{name: "256 quantises RGB", level: Level256, colour: orange, want: Indexed(208)},
+		{name: "256 keeps indexed", level: Level256, colour: Indexed(3), want: Indexed(3)},
+		{name: "16 quantises RGB", level: Level16, colour: color.RGBA{R: 200, A: 255}, want: RED},
+		{name: "16 maps system colours", level: Level16, colour: Indexed(4), want: BLUE},
+		{name: "16 maps bright system colours", level: Level16, colour: Indexed(9), want: RED + HighIntensityOffset},
+		{name: "16 quantises indexed", level: Level16, colour: Indexed(46), want: GREEN + HighIntensityOffset},
+		{name: "16 keeps colours", level: Level16, colour: CYAN + BackgroundOffset, want: CYAN + BackgroundOffset},
+		{name: "None removes colours", level: LevelNone, colour: RED, want: nil},
+		{name: "None removes RGB", level: LevelNone, colour: orange, want: nil},
+		{name: "Nil stays nil", level: Level16, colour: nil, want: nil},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.name, func(t *testing.T) {
+				if got := test.level.Convert(test.colour); got != test.want {
+					t.Fatalf("Converted %v to %v instead of %v", test.colour, got, test.want)
+				}
+			},
+		)
+	}
+}
+
+func TestLevel_String(t *testing.T) {
+	if LevelTrueColour.String() != "TRUECOLOUR" || Level256.String() != "256" || Level(99).String() != "UNKNOWN" {
+		t.Fatal("Wrong level names")
+	}
+}
@@ -0,0 +1,76 @@
+package ansi
+
+// ParseMode selects how strictly sequences are parsed.
+type ParseMode byte
+
+const (
+	// Strict rejects any sequence that doesn't follow the standard, which is useful for validation.
+	Strict ParseMode = iota
+	// Lenient follows the behaviour of xterm, treating empty and missing parameters as 0,
+	// clamping overlong values and ignoring codes that are out of range or incomplete.
+	// Sequences that aren't made of numeric parameters are still rejected.
+	Lenient
+	// StrictC1 works the same as Strict, but also accepts the 8-bit CSI and OSC introducers of ansi.ScanCodesC1.
+	StrictC1
+	// LenientC1 works the same as Lenient, but also accepts the 8-bit CSI and OSC introducers of ansi.ScanCodesC1.
+	LenientC1
+)
+
+// lenient reports whether the mode follows the behaviour of xterm.
+func (m ParseMode) lenient() bool {
+	return m == Lenient || m == LenientC1
+}
+
+// c1 reports whether the mode accepts 8-bit C1 introducers.
+func (m ParseMode) c1() bool {
+	return m == StrictC1 || m == LenientC1
+}
+
+// ParsePainter creates a painter from an ANSI sequence, as parsed by ansi.ScanCodes.
+// It returns an error if the sequence is invalid under this mode.
+func (m ParseMode) ParsePainter(sequence []byte) (p *Painter, err error) {
+	var codes []Code
+	codes, err = m.ParseCodes(sequence)
+	if err != nil {
+		return nil, err
+	}
+
+	p = DefaultPainter()
+	p.ApplyCodes(codes...)
+	return
+}
+
+// Apply updates the painter with an ANSI sequence, the same as ansi.Painter.Apply but parsed under this mode.
+func (m ParseMode) Apply(p *Painter, sequence []byte) error {
+	switch classify(sequence, m.c1()) {
+	case KindSGR:
+		var buffer [MaxParameters]Code
+		var codes, err = m.appendCodes(buffer[:0], sequence)
+		if err != nil {
+			return err
+		}
+
+		p.ApplyCodes(codes...)
+	case KindOSC:
+		if link, ok := parseHyperlink(sequence, m.c1()); ok {
+			p.Link = link
+		}
+	}
+
+	return nil
+}
+
+func (m ParseMode) String() string {
+	switch m {
+	case Strict:
+		return "STRICT"
+	case Lenient:
+		return "LENIENT"
+	case StrictC1:
+		return "STRICT_C1"
+	case LenientC1:
+		return "LENIENT_C1"
+	default:
+		return "UNKNOWN"
+	}
+}
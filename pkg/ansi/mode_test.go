@@ -0,0 +1,169 @@
+package ansi
+
+import (
+	"errors"
+	"image/color"
+	"testing"
+)
+
+func TestParseMode_ParseParameters(t *testing.T) {
+	var actual, err = Lenient.ParseParameters([]byte("\x1b[;99999;1:m"))
+	if err != nil {
+		t.Fatal("Lenient mode shouldn't error on empty or overlong parameters")
+	}
+
+	var expected = []Parameter{{Empty: true}, {Value: MaxParameter}, {Value: 1}, {Sub: true, Empty: true}}
+	if len(actual) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, actual)
+	}
+
+	for i := range expected {
+		if actual[i] != expected[i] {
+			t.Fatalf("Expected %v, got %v", expected, actual)
+		}
+	}
+
+	var long = []byte("\x1b[")
+	for i := 0; i < MaxParameters+8; i++ {
+		long = append(long, '1', Separator)
+	}
+	long[len(long)-1] = EndCode
+
+	if actual, _ = Lenient.ParseParameters(long); len(actual) != MaxParameters {
+		t.Fatal("Lenient mode should drop extra parameters")
+	}
+
+	if actual, _ = Strict.ParseParameters(long); len(actual) != MaxParameters+8 {
+		t.Fatal("Strict mode should keep every parameter")
+	}
+
+	if _, err = Lenient.ParseParameters([]byte("\x1b[1;am")); err == nil {
+		t.Fatal("Lenient mode should still reject non-numeric parameters")
+	}
+}
+
+func TestParseMode_ParseCodes(t *testing.T) {
+	var tests = []struct {
+		sequence string
+		expected []Code
+	}{
+		{sequence: "\x1b[m", expected: []Code{{Colour: NORMAL}}},
+		{sequence: "\x1b[;31m", expected: []Code{{Colour: NORMAL}, {Colour: RED}}},
+		{sequence: "\x1b[1;300;31m", expected: []Code{{Colour: BOLD}, {Colour: RED}}},
+		{sequence: "\x1b[38;5;300;31m", expected: []Code{{Colour: RED}}},
+		{sequence: "\x1b[38;2;1;2;999;1m", expected: []Code{{Colour: BOLD}}},
+		{sequence: "\x1b[1;38;5m", expected: []Code{{Colour: BOLD}}},
+		{sequence: "\x1b[38;7;1m", expected: []Code{{Colour: BOLD}}},
+		{sequence: "\x1b[4:9;1m", expected: []Code{{Colour: BOLD}}},
+		{sequence: "\x1b[38;5;m", expected: []Code{{Colour: EXTENDED_TEXT, Extended: Indexed(0)}}},
+		{
+			sequence: "\x1b[38:2::255:128:0m",
+			expected: []Code{{Colour: EXTENDED_TEXT, Extended: color.RGBA{R: 255, G: 128, A: 255}}},
+		},
+	}
+
+	for _, test := range tests {
+		var actual, err = Lenient.ParseCodes([]byte(test.sequence))
+		if err != nil {
+			t.Fatalf("Sequence %q shouldn't error in lenient mode: %v", test.sequence, err)
+		}
+
+		if len(actual) != len(test.expected) {
+			t.Fatalf("Sequence %q expected %v, got %v", test.sequence, test.expected, actual)
+		}
+
+		for i := range actual {
+			if actual[i] != test.expected[i] {
+				t.Fatalf("Sequence %q expected %v, got %v", test.sequence, test.expected, actual)
+			}
+		}
+
+		if _, err = Strict.ParseCodes([]byte(test.sequence)); err == nil && test.sequence != "\x1b[38:2::255:128:0m" {
+			t.Fatalf("Sequence %q should error in strict mode", test.sequence)
+		}
+	}
+}
+
+func TestParseMode_ParseColourCodes(t *testing.T) {
+	var actual, err = Lenient.ParseColourCodes([]byte("\x1b[;300;31m"))
+	if err != nil || len(actual) != 2 || actual[0] != NORMAL || actual[1] != RED {
+		t.Fatalf("Lenient mode parsed colour codes incorrectly: %v", actual)
+	}
+
+	if _, err = ParseColourCodes([]byte("\x1b[m")); err == nil {
+		t.Fatal("Strict mode should reject empty parameters")
+	}
+}
+
+func TestParseMode_Apply(t *testing.T) {
+	var p = NewPainterFromSequence([]byte("\x1b[1;31m"))
+
+	if err := Lenient.Apply(p, []byte("\x1b[m")); err != nil {
+		t.Fatal("Lenient mode shouldn't error on an empty sequence")
+	}
+
+	if *p != *DefaultPainter() {
+		t.Fatal("Empty sequence should reset the painter")
+	}
+
+	p, _ = Lenient.ParsePainter([]byte("\x1b[;1;38;5;999;32m"))
+	if !p.Bold || p.TextColor() != GREEN {
+		t.Fatal("Lenient painter parsed incorrectly")
+	}
+
+	if _, err := Strict.ParsePainter([]byte("\x1b[;1m")); err == nil {
+		t.Fatal("Strict painter should reject empty parameters")
+	}
+}
+
+func TestParseMode_C1(t *testing.T) {
+	const sequence = "\x9b31m"
+
+	t.Run(
+		"Default", func(t *testing.T) {
+			if _, err := ParseParameters([]byte(sequence)); !errors.Is(err, ErrNotSequence) {
+				t.Fatal("8-bit parameters should be rejected without C1", err)
+			}
+			if _, err := ParseCodes([]byte(sequence)); !errors.Is(err, ErrNotSequence) {
+				t.Fatal("8-bit codes should be rejected without C1", err)
+			}
+			if _, err := Lenient.ParseColourCodes([]byte(sequence)); !errors.Is(err, ErrNotSequence) {
+				t.Fatal("8-bit colours should be rejected in Lenient mode", err)
+			}
+			if _, err := ParsePainter([]byte(sequence)); err == nil {
+				t.Fatal("8-bit painter should be rejected without C1")
+			}
+
+			defer func() {
+				if recover() == nil {
+					t.Fatal("MustParseColourCodes should panic on an 8-bit sequence")
+				}
+			}()
+			MustParseColourCodes([]byte(sequence))
+		},
+	)
+
+	t.Run(
+		"C1", func(t *testing.T) {
+			if codes, err := StrictC1.ParseCodes([]byte(sequence)); err != nil || len(codes) != 1 || codes[0].Colour != RED {
+				t.Fatal("8-bit codes should be parsed in C1 mode", codes, err)
+			}
+			if colours, err := LenientC1.ParseColourCodes([]byte("\x9b;31m")); err != nil || len(colours) != 2 {
+				t.Fatal("8-bit colours should be parsed in Lenient C1 mode", colours, err)
+			}
+
+			var err *ParseError
+			if _, e := StrictC1.ParseCodes([]byte("\x9b1;300m")); !errors.As(e, &err) || err.Offset != 3 {
+				t.Fatal("8-bit errors should be reported after the introducer", e)
+			}
+
+			var p = DefaultPainter()
+			if e := StrictC1.Apply(p, []byte("\x9b32m")); e != nil || p.TextColor() != GREEN {
+				t.Fatal("8-bit sequence wasn't applied in C1 mode", e)
+			}
+			if e := StrictC1.Apply(p, []byte("\x9d8;;https://a\x9c")); e != nil || p.Link == nil || p.Link.URI != "https://a" {
+				t.Fatal("8-bit hyperlink wasn't applied in C1 mode", e)
+			}
+		},
+	)
+}
@@ -0,0 +1,187 @@
+package ansi
+
+import (
+	"image/color"
+	"math"
+)
+
+// Metric measures how different two colours are, where smaller is closer and 0 is the same colour.
+// Colours are compared without their alpha.
+type Metric func(x, y color.RGBA) float64
+
+// Euclidean is the straight line distance between two colours in RGB space.
+func Euclidean(x, y color.RGBA) float64 {
+	var dr, dg, db = float64(x.R) - float64(y.R), float64(x.G) - float64(y.G), float64(x.B) - float64(y.B)
+	return math.Sqrt(dr*dr + dg*dg + db*db)
+}
+
+// Redmean is a weighted RGB distance that follows human perception more closely than Euclidean,
+// as described in https://www.compuphase.com/cmetric.htm.
+func Redmean(x, y color.RGBA) float64 {
+	var mean = (float64(x.R) + float64(y.R)) / 2
+	var dr, dg, db = float64(x.R) - float64(y.R), float64(x.G) - float64(y.G), float64(x.B) - float64(y.B)
+	return math.Sqrt((2+mean/256)*dr*dr + 4*dg*dg + (2+(255-mean)/256)*db*db)
+}
+
+// CIE76 is the CIE 1976 colour difference, ΔE*ab, the straight line distance between two colours in CIELAB space.
+func CIE76(x, y color.RGBA) float64 {
+	var a, b = toLab(x), toLab(y)
+	var dl, da, db = a.L - b.L, a.A - b.A, a.B - b.B
+	return math.Sqrt(dl*dl + da*da + db*db)
+}
+
+// CIEDE2000 is the CIE 2000 colour difference, ΔE*00, the most accurate and slowest of the metrics.
+func CIEDE2000(x, y color.RGBA) float64 {
+	return deltaE2000(toLab(x), toLab(y))
+}
+
+// Nearest returns the Colour in the palette that is closest to c, as a text colour.
+// Bright colours are returned with the HighIntensityOffset.
+// The metric defaults to Redmean if nil.
+func (p *Palette) Nearest(c color.Color, metric Metric) Colour {
+	if metric == nil {
+		metric = Redmean
+	}
+
+	var target = opaque(c)
+	var best, distance = 0, math.Inf(1)
+	for i, candidate := range p.Colours {
+		if d := metric(target, candidate); d < distance {
+			best, distance = i, d
+		}
+	}
+
+	if best >= 8 {
+		return BLACK + HighIntensityOffset + Colour(best-8)
+	}
+	return BLACK + Colour(best)
+}
+
+// NearestColour returns the text Colour that is closest to c, using the Campbell palette like Colour.RGBA does.
+// The metric defaults to Redmean if nil.
+func NearestColour(c color.Color, metric Metric) Colour {
+	return Campbell.Nearest(c, metric)
+}
+
+// NearestIndexed returns the Indexed colour that is closest to c.
+// Only the colour cube and greyscale ramp are considered, since the first 16 colours change between terminals.
+// The metric defaults to Redmean if nil.
+func NearestIndexed(c color.Color, metric Metric) Indexed {
+	if metric == nil {
+		metric = Redmean
+	}
+
+	var target = opaque(c)
+	var best, distance = Indexed(cubeOffset), math.Inf(1)
+	for i := cubeOffset; i <= 255; i++ {
+		var r, g, b, _ = Indexed(i).RGBA8()
+		if d := metric(target, color.RGBA{R: uint8(r), G: uint8(g), B: uint8(b), A: 255}); d < distance {
+			best, distance = Indexed(i), d
+		}
+	}
+
+	return best
+}
+
+// opaque converts any colour to RGB without premultiplied alpha, dropping the alpha.
+func opaque(c color.Color) color.RGBA {
+	var n = color.NRGBAModel.Convert(c).(color.NRGBA)
+	return color.RGBA{R: n.R, G: n.G, B: n.B, A: 255}
+}
+
+// lab is a colour in the CIELAB colour space.
+type lab struct {
+	L, A, B float64
+}
+
+// toLab converts an sRGB colour to CIELAB, using the D65 white point.
+func toLab(c color.RGBA) lab {
+	var linear = func(v uint8) float64 {
+		var f = float64(v) / 255
+		if f <= 0.04045 {
+			return f / 12.92
+		}
+		return math.Pow((f+0.055)/1.055, 2.4)
+	}
+	var r, g, b = linear(c.R), linear(c.G), linear(c.B)
+
+	var x = (0.4124564*r + 0.3575761*g + 0.1804375*b) / 0.95047
+	var y = 0.2126729*r + 0.7151522*g + 0.0721750*b
+	var z = (0.0193339*r + 0.1191920*g + 0.9503041*b) / 1.08883
+
+	var f = func(t float64) float64 {
+		if t > 216.0/24389 {
+			return math.Cbrt(t)
+		}
+		return (24389.0/27*t + 16) / 116
+	}
+	var fx, fy, fz = f(x), f(y), f(z)
+
+	return lab{L: 116*fy - 16, A: 500 * (fx - fy), B: 200 * (fy - fz)}
+}
+
+// deltaE2000 is the CIEDE2000 colour difference between two CIELAB colours,
+// following "The CIEDE2000 Color-Difference Formula" by Sharma, Wu and Dalal.
+func deltaE2000(x, y lab) float64 {
+	const pow25to7 = 6103515625.0
+	var radians = func(degrees float64) float64 { return degrees * math.Pi / 180 }
+	var hue = func(b, a float64) float64 {
+		if a == 0 && b == 0 {
+			return 0
+		}
+		var h = math.Atan2(b, a) * 180 / math.Pi
+		if h < 0 {
+			h += 360
+		}
+		return h
+	}
+
+	var c1, c2 = math.Hypot(x.A, x.B), math.Hypot(y.A, y.B)
+	var cMean7 = math.Pow((c1+c2)/2, 7)
+	var g = 0.5 * (1 - math.Sqrt(cMean7/(cMean7+pow25to7)))
+
+	var a1, a2 = (1 + g) * x.A, (1 + g) * y.A
+	var c1p, c2p = math.Hypot(a1, x.B), math.Hypot(a2, y.B)
+	var h1p, h2p = hue(x.B, a1), hue(y.B, a2)
+
+	var dl = y.L - x.L
+	var dc = c2p - c1p
+	var dh float64
+	if c1p*c2p != 0 {
+		dh = h2p - h1p
+		if dh > 180 {
+			dh -= 360
+		} else if dh < -180 {
+			dh += 360
+		}
+	}
+	var dH = 2 * math.Sqrt(c1p*c2p) * math.Sin(radians(dh/2))
+
+	var lMean = (x.L + y.L) / 2
+	var cMean = (c1p + c2p) / 2
+	var hMean = h1p + h2p
+	if c1p*c2p != 0 {
+		switch {
+		case math.Abs(h1p-h2p) <= 180:
+			hMean /= 2
+		case hMean < 360:
+			hMean = (hMean + 360) / 2
+		default:
+			hMean = (hMean - 360) / 2
+		}
+	}
+
+	var t = 1 - 0.17*math.Cos(radians(hMean-30)) + 0.24*math.Cos(radians(2*hMean)) +
+		0.32*math.Cos(radians(3*hMean+6)) - 0.20*math.Cos(radians(4*hMean-63))
+	var dTheta = 30 * math.Exp(-math.Pow((hMean-275)/25, 2))
+	var cMeanP7 = math.Pow(cMean, 7)
+	var rc = 2 * math.Sqrt(cMeanP7/(cMeanP7+pow25to7))
+	var lSquare = (lMean - 50) * (lMean - 50)
+	var sl = 1 + 0.015*lSquare/math.Sqrt(20+lSquare)
+	var sc = 1 + 0.045*cMean
+	var sh = 1 + 0.015*cMean*t
+	var rt = -math.Sin(radians(2*dTheta)) * rc
+
+	var l, c, h = dl / sl, dc / sc, dH / sh
+	return math.Sqrt(l*l + c*c + h*h + rt*c*h)
+}
@@ -0,0 +1,122 @@
+package ansi
+
+import (
+	"image/color"
+	"math"
+	"testing"
+)
+
+func Test_deltaE2000(t *testing.T) {
+	// Test data from "The CIEDE2000 Color-Difference Formula" by Sharma, Wu and Dalal
+	var tests = []struct {
+		x, y lab
+		want float64
+	}{
+		{x: lab{50, 2.6772, -79.7751}, y: lab{50, 0, -82.7485}, want: 2.0425},
+		{x: lab{50, 0, 0}, y: lab{50, -1, 2}, want: 2.3669},
+		{x: lab{50, 2.5, 0}, y: lab{73, 25, -18}, want: 27.1492},
+		{x: lab{60.2574, -34.0099, 36.2677}, y: lab{60.4626, -34.1751, 39.4387}, want: 1.2644},
+		{x: lab{2.0776, 0.0795, -1.1350}, y: lab{0.9033, -0.0636, -0.5514}, want: 0.9082},
+	}
+
+	for _, test := range tests {
+		if got := deltaE2000(test.x, test.y); math.Abs(got-test.want) > 0.0001 {
+			t.Fatalf("ΔE2000 of %v and %v is %.4f instead of %.4f", test.x, test.y, got, test.want)
+		}
+		if got := deltaE2000(test.y, test.x); math.Abs(got-test.want) > 0.0001 {
+			t.Fatalf("ΔE2000 of %v and %v isn't symmetric", test.x, test.y)
+		}
+	}
+}
+
+func Test_toLab(t *testing.T) {
+	var white = toLab(hex(0xFFFFFF))
+	if math.Abs(white.L-100) > 0.01 || math.Abs(white.A) > 0.01 || math.Abs(white.B) > 0.01 {
+		t.Fatal("White isn't L=100", white)
+	}
+	var red = toLab(hex(0xFF0000))
+	if math.Abs(red.L-53.24) > 0.01 || math.Abs(red.A-80.09) > 0.01 || math.Abs(red.B-67.20) > 0.01 {
+		t.Fatal("Red has the wrong CIELAB value", red)
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	var metrics = map[string]Metric{"Euclidean": Euclidean, "Redmean": Redmean, "CIE76": CIE76, "CIEDE2000": CIEDE2000}
+	for name, metric := range metrics {
+		t.Run(
+			name, func(t *testing.T) {
+				if metric(hex(0x123456), hex(0x123456)) != 0 {
+					t.Fatal("Distance to the same colour isn't 0")
+				}
+				if metric(hex(0xFF0000), hex(0xEE1111)) >= metric(hex(0xFF0000), hex(0x0000FF)) {
+					t.Fatal("Similar colours are further apart than different colours")
+				}
+			},
+		)
+	}
+
+	if Euclidean(hex(0x000000), hex(0x030400)) != 5 {
+		t.Fatal("Wrong Euclidean distance")
+	}
+}
+
+func TestPalette_Nearest(t *testing.T) {
+	var tests = []struct {
+		colour color.Color
+		want   Colour
+	}{
+		{colour: hex(0x000000), want: BLACK},
+		{colour: hex(0xC00000), want: RED},
+		{colour: hex(0xFF5050), want: RED + HighIntensityOffset},
+		{colour: hex(0x10B010), want: GREEN},
+		{colour: hex(0xFFFFFF), want: WHITE + HighIntensityOffset},
+		{colour: Indexed(4), want: BLUE},
+		{colour: color.Gray{Y: 0xAA}, want: WHITE},
+	}
+
+	for _, test := range tests {
+		if got := VGA.Nearest(test.colour, nil); got != test.want {
+			t.Fatalf("Nearest colour to %v is %s instead of %s", test.colour, got, test.want)
+		}
+	}
+
+	t.Run(
+		"Colours are their own nearest", func(t *testing.T) {
+			for c := BLACK; c <= WHITE; c++ {
+				if NearestColour(c, CIEDE2000) != c || NearestColour(c+HighIntensityOffset, Euclidean) != c+HighIntensityOffset {
+					t.Fatalf("Colour %s isn't its own nearest colour", c)
+				}
+			}
+		},
+	)
+}
+
+func TestNearestIndexed(t *testing.T) {
+	var tests = []struct {
+		colour color.Color
+		want   Indexed
+	}{
+		{colour: hex(0xFF0000), want: 196},
+		{colour: hex(0xFF8800), want: 208},
+		{colour: hex(0x000000), want: 16},
+		{colour: hex(0x090909), want: 232},
+		{colour: hex(0x808080), want: 244},
+		{colour: Indexed(9), want: 196},
+	}
+
+	for _, test := range tests {
+		if got := NearestIndexed(test.colour, Euclidean); got != test.want {
+			t.Fatalf("Nearest indexed colour to %v is %d instead of %d", test.colour, got, test.want)
+		}
+	}
+
+	t.Run(
+		"Indexed colours are their own nearest", func(t *testing.T) {
+			for i := cubeOffset; i <= 255; i++ {
+				if NearestIndexed(Indexed(i), nil) != Indexed(i) {
+					t.Fatalf("Indexed colour %d isn't its own nearest colour", i)
+				}
+			}
+		},
+	)
+}
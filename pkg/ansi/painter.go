@@ -7,12 +7,27 @@ import (
 // Painter is a struct that contains a text (foreground) and background colours.
 // Can be created from an ANSI sequence and has helper methods to read sequence.
 // Nil values means the colour should be ignored and a default used.
+// The remaining fields are the SGR attributes of the same name.
 type Painter struct {
 	text color.Color
 	back color.Color
 
-	Bold   bool
-	Italic bool
+	Bold          bool
+	Faint         bool
+	Italic        bool
+	Underline     UnderlineStyle
+	SlowBlink     bool
+	RapidBlink    bool
+	Reverse       bool
+	Conceal       bool
+	Strikethrough bool
+	Overline      bool
+	Framed        bool
+	Encircled     bool
+
+	// Link is the active hyperlink, or nil if text isn't part of a link.
+	// It is set by OSC 8 sequences and isn't affected by SGR resets.
+	Link *Hyperlink
 }
 
 // NewPainter creates a painter with the given foreground and background colors.
@@ -32,27 +47,29 @@ func DefaultPainter() *Painter {
 // Panics if sequence is invalid.
 func NewPainterFromSequence(sequence []byte) (p *Painter) {
 	p = DefaultPainter()
+	p.ApplyCodes(MustParseCodes(sequence)...)
+	return
+}
+
+// ParsePainter creates a painter from an ANSI sequence, as parsed by ansi.ScanCodes.
+// Unlike ansi.NewPainterFromSequence, it returns an error if the sequence is invalid.
+func ParsePainter(sequence []byte) (p *Painter, err error) {
+	return Strict.ParsePainter(sequence)
+}
+
+// Apply updates the painter with an ANSI sequence, as parsed by ansi.ScanCodes.
+// Sequences are cumulative, so a stream can be tracked by applying every sequence in order.
+// Colour sequences update the style, OSC 8 sequences update the link, and any other token is ignored.
+// The painter is left unchanged if the sequence is invalid.
+func (p *Painter) Apply(sequence []byte) error {
+	return Strict.Apply(p, sequence)
+}
 
-	var codes = MustParseColourCodes(sequence)
+// ApplyCodes updates the painter with the given codes, in order.
+func (p *Painter) ApplyCodes(codes ...Code) {
 	for _, code := range codes {
-		switch code {
-		case NORMAL:
-			continue
-		case BOLD:
-			p.Bold = true
-		case ITALIC:
-			p.Italic = true
-		default:
-			switch {
-			case code.IsText():
-				p.text = code
-			case code.IsBackground():
-				p.back = code
-			}
-		}
+		p.apply(code)
 	}
-
-	return
 }
 
 // TextColor returns the underlying foreground color.
@@ -64,3 +81,76 @@ func (p *Painter) TextColor() color.Color {
 func (p *Painter) BackgroundColor() color.Color {
 	return p.back
 }
+
+// Downgrade returns a copy of the painter with its colours converted to the nearest ones the level supports,
+// so that its sequences can be written to a terminal with that level.
+// At LevelNone the colours are removed and the other attributes are kept.
+func (p *Painter) Downgrade(level Level) *Painter {
+	var res = *p
+	res.text = level.Convert(p.text)
+	res.back = level.Convert(p.back)
+	return &res
+}
+
+// apply updates the painter with a single code.
+func (p *Painter) apply(code Code) {
+	switch code.Colour {
+	case NORMAL:
+		*p = Painter{Link: p.Link}
+	case BOLD:
+		p.Bold = true
+	case FAINT:
+		p.Faint = true
+	case ITALIC:
+		p.Italic = true
+	case UNDERLINE, DOUBLE_UNDERLINE, NOT_UNDERLINED:
+		p.Underline = code.Underline
+	case SLOW_BLINK:
+		p.SlowBlink = true
+	case RAPID_BLINK:
+		p.RapidBlink = true
+	case REVERSE:
+		p.Reverse = true
+	case CONCEAL:
+		p.Conceal = true
+	case STRIKETHROUGH:
+		p.Strikethrough = true
+	case NORMAL_INTENSITY:
+		p.Bold, p.Faint = false, false
+	case NOT_ITALIC:
+		p.Italic = false
+	case NOT_BLINKING:
+		p.SlowBlink, p.RapidBlink = false, false
+	case NOT_REVERSED:
+		p.Reverse = false
+	case REVEAL:
+		p.Conceal = false
+	case NOT_STRIKETHROUGH:
+		p.Strikethrough = false
+	case FRAMED:
+		p.Framed = true
+	case ENCIRCLED:
+		p.Encircled = true
+	case OVERLINE:
+		p.Overline = true
+	case NOT_FRAMED:
+		p.Framed, p.Encircled = false, false
+	case NOT_OVERLINED:
+		p.Overline = false
+	case EXTENDED_TEXT:
+		p.text = code.Extended
+	case EXTENDED_BACKGROUND:
+		p.back = code.Extended
+	case DEFAULT_TEXT:
+		p.text = nil
+	case DEFAULT_BACKGROUND:
+		p.back = nil
+	default:
+		switch {
+		case code.Colour.IsText():
+			p.text = code.Colour
+		case code.Colour.IsBackground():
+			p.back = code.Colour
+		}
+	}
+}
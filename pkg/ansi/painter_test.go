@@ -1,13 +1,18 @@
 package ansi
 
-import "testing"
+import (
+	"bufio"
+	"image/color"
+	"strings"
+	"testing"
+)
 
 func TestPainter_NewPainterFromSequence(t *testing.T) {
 	var p = NewPainterFromSequence(
 		[]byte{
 			EscapeCode, StartCode,
 			byte('1'), Separator,
-			byte('2'), Separator,
+			byte('3'), Separator,
 			byte('3'), byte('1'), Separator,
 			byte('4'), byte('2'), Separator,
 			byte('9'), byte('1'),
@@ -31,3 +36,158 @@ func TestPainter_NewPainterFromSequence(t *testing.T) {
 		t.Fatal("Failed to detect background colour")
 	}
 }
+
+func TestPainter_NewPainterFromSequenceIndexed(t *testing.T) {
+	var p = NewPainterFromSequence([]byte("\x1b[38;5;208;48;5;232m"))
+
+	if p.TextColor() != Indexed(208) {
+		t.Fatal("Failed to detect indexed foreground colour")
+	}
+
+	if p.BackgroundColor() != Indexed(232) {
+		t.Fatal("Failed to detect indexed background colour")
+	}
+}
+
+func TestPainter_NewPainterFromSequenceRGB(t *testing.T) {
+	var p = NewPainterFromSequence([]byte("\x1b[1;38;2;255;128;0;44;48;2;0;0;0;31m"))
+
+	if !p.Bold {
+		t.Fatal("Failed to detect BOLD")
+	}
+
+	if p.TextColor() != RED {
+		t.Fatal("Later foreground colour should override RGB colour")
+	}
+
+	if p.BackgroundColor() != (color.RGBA{A: 255}) {
+		t.Fatal("Failed to detect RGB background colour")
+	}
+
+	p = NewPainterFromSequence([]byte("\x1b[38;2;255;128;0m"))
+	if p.TextColor() != (color.RGBA{R: 255, G: 128, A: 255}) {
+		t.Fatal("Failed to detect RGB foreground colour")
+	}
+}
+
+func TestPainter_NewPainterFromSequenceAttributes(t *testing.T) {
+	var p = NewPainterFromSequence([]byte("\x1b[2;4:3;5;7;8;9;53;51;52m"))
+
+	if !p.Faint || p.Bold || p.Italic {
+		t.Fatal("FAINT should be distinct from BOLD and ITALIC")
+	}
+
+	if p.Underline != CurlyUnderline {
+		t.Fatal("Failed to detect underline style")
+	}
+
+	if !p.SlowBlink || p.RapidBlink || !p.Reverse || !p.Conceal || !p.Strikethrough {
+		t.Fatal("Failed to detect attributes")
+	}
+
+	if !p.Overline || !p.Framed || !p.Encircled {
+		t.Fatal("Failed to detect rare attributes")
+	}
+
+	p = NewPainterFromSequence([]byte("\x1b[1;2;3;4;5;6;7;8;9;53;51;22;23;24;25;27;28;29;54;55m"))
+	if *p != *DefaultPainter() {
+		t.Fatal("Off codes should clear their attributes")
+	}
+
+	p = NewPainterFromSequence([]byte("\x1b[21m"))
+	if p.Underline != DoubleUnderline {
+		t.Fatal("DOUBLE_UNDERLINE should match the 4:2 form")
+	}
+}
+
+func TestPainter_Apply(t *testing.T) {
+	t.Run(
+		"Cumulative", func(t *testing.T) {
+			var (
+				p       = DefaultPainter()
+				scanner = bufio.NewScanner(strings.NewReader("\x1b[1mHello \x1b[31mWorld\x1b[44m!"))
+				styles  []Painter
+			)
+			scanner.Split(ScanCodes)
+
+			for scanner.Scan() {
+				var token = scanner.Bytes()
+				if IsSequence(token) {
+					if err := p.Apply(token); err != nil {
+						t.Fatal("Errored while applying sequence")
+					}
+				} else {
+					styles = append(styles, *p)
+				}
+			}
+
+			if len(styles) != 3 {
+				t.Fatal("Scanned the wrong number of text tokens")
+			}
+
+			if !styles[0].Bold || styles[0].TextColor() != nil {
+				t.Fatal("First text should only be bold")
+			}
+
+			if !styles[1].Bold || styles[1].TextColor() != RED || styles[1].BackgroundColor() != nil {
+				t.Fatal("Second text should be bold red")
+			}
+
+			if !styles[2].Bold || styles[2].TextColor() != RED || styles[2].BackgroundColor() != BLUE+BackgroundOffset {
+				t.Fatal("Third text should be bold red on blue")
+			}
+		},
+	)
+
+	t.Run(
+		"Reset", func(t *testing.T) {
+			var p = NewPainterFromSequence([]byte("\x1b[1;2;3;31;44m"))
+
+			_ = p.Apply([]byte("\x1b[22;39m"))
+			if p.Bold || p.Faint || !p.Italic || p.TextColor() != nil || p.BackgroundColor() != BLUE+BackgroundOffset {
+				t.Fatal("NORMAL_INTENSITY and DEFAULT_TEXT reset the wrong attributes")
+			}
+
+			_ = p.Apply([]byte("\x1b[49m"))
+			if p.BackgroundColor() != nil {
+				t.Fatal("DEFAULT_BACKGROUND should reset the background colour")
+			}
+
+			_ = p.Apply([]byte("\x1b[4;0;32m"))
+			if p.Italic || p.Underline != NoUnderline || p.TextColor() != GREEN {
+				t.Fatal("NORMAL should reset everything before it")
+			}
+		},
+	)
+
+	t.Run(
+		"Invalid", func(t *testing.T) {
+			var p = NewPainterFromSequence([]byte("\x1b[1m"))
+
+			if p.Apply([]byte("\x1b[0;38;5m")) == nil {
+				t.Fatal("Invalid sequence should error")
+			}
+
+			if !p.Bold {
+				t.Fatal("Invalid sequence shouldn't change the painter")
+			}
+		},
+	)
+}
+
+func TestPainter_ApplyAllocations(t *testing.T) {
+	var p = DefaultPainter()
+	var sequence = []byte("\x1b[0;1;4:3;31;48;5;21m")
+
+	var allocs = testing.AllocsPerRun(
+		100, func() {
+			if err := p.Apply(sequence); err != nil {
+				panic(err)
+			}
+		},
+	)
+
+	if allocs != 0 {
+		t.Fatalf("Applying a sequence without RGB colours shouldn't allocate, got %v allocations", allocs)
+	}
+}
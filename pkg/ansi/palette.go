@@ -0,0 +1,195 @@
+package ansi
+
+import "image/color"
+
+// Palette is a terminal colour scheme, used to resolve ANSI colours into RGB.
+// Colours holds the 8 normal colours, from BLACK to WHITE, followed by their 8 bright versions.
+type Palette struct {
+	Name string
+
+	Colours    [16]color.RGBA
+	Foreground color.RGBA
+	Background color.RGBA
+	Cursor     color.RGBA
+}
+
+// Colour returns the RGB value of the Colour in this palette.
+// Text and background versions of a Colour share a value.
+// Codes that aren't colours are black.
+func (p *Palette) Colour(c Colour) color.RGBA {
+	if !c.IsText() && !c.IsBackground() {
+		return color.RGBA{A: 255}
+	}
+
+	var index = c.Normalize() - BLACK
+	if c.IsHighIntensity() {
+		index += 8
+	}
+
+	return p.Colours[index]
+}
+
+// Resolve returns the RGB value of any colour in this palette.
+// Colour values and the first 16 Indexed colours are taken from the palette,
+// while the rest of the Indexed colours and any other colour are the same in every palette.
+// Nil colours resolve to the default foreground.
+func (p *Palette) Resolve(c color.Color) color.RGBA {
+	switch c := c.(type) {
+	case nil:
+		return p.Foreground
+	case Colour:
+		return p.Colour(c)
+	case Indexed:
+		if c < cubeOffset {
+			return p.Colours[c]
+		}
+		var r, g, b, a = c.RGBA8()
+		return color.RGBA{R: uint8(r), G: uint8(g), B: uint8(b), A: uint8(a)}
+	default:
+		return color.RGBAModel.Convert(c).(color.RGBA)
+	}
+}
+
+// hex is a helper function to write palette colours as 0xRRGGBB.
+func hex(rgb uint32) color.RGBA {
+	return color.RGBA{R: uint8(rgb >> 16), G: uint8(rgb >> 8), B: uint8(rgb), A: 255}
+}
+
+// Campbell is the default colour scheme of Windows Terminal, and the one used by Colour.RGBA.
+var Campbell = Palette{
+	Name: "Campbell",
+	Colours: [16]color.RGBA{
+		hex(0x0C0C0C), hex(0xC50F1F), hex(0x13A10E), hex(0xC19C00),
+		hex(0x0037DA), hex(0x881798), hex(0x3A96DD), hex(0xCCCCCC),
+		hex(0x767676), hex(0xE74856), hex(0x16C60C), hex(0xF9F1A5),
+		hex(0x3B78FF), hex(0xB4009E), hex(0x61D6D6), hex(0xF2F2F2),
+	},
+	Foreground: hex(0xCCCCCC),
+	Background: hex(0x0C0C0C),
+	Cursor:     hex(0xFFFFFF),
+}
+
+// XTerm is the default colour scheme of xterm, and the one used by Indexed.RGBA.
+var XTerm = Palette{
+	Name: "xterm",
+	Colours: [16]color.RGBA{
+		hex(0x000000), hex(0xCD0000), hex(0x00CD00), hex(0xCDCD00),
+		hex(0x0000EE), hex(0xCD00CD), hex(0x00CDCD), hex(0xE5E5E5),
+		hex(0x7F7F7F), hex(0xFF0000), hex(0x00FF00), hex(0xFFFF00),
+		hex(0x5C5CFF), hex(0xFF00FF), hex(0x00FFFF), hex(0xFFFFFF),
+	},
+	Foreground: hex(0x000000),
+	Background: hex(0xFFFFFF),
+	Cursor:     hex(0x000000),
+}
+
+// VGA is the colour scheme of the IBM VGA text mode, as used by the Linux console.
+var VGA = Palette{
+	Name: "VGA",
+	Colours: [16]color.RGBA{
+		hex(0x000000), hex(0xAA0000), hex(0x00AA00), hex(0xAA5500),
+		hex(0x0000AA), hex(0xAA00AA), hex(0x00AAAA), hex(0xAAAAAA),
+		hex(0x555555), hex(0xFF5555), hex(0x55FF55), hex(0xFFFF55),
+		hex(0x5555FF), hex(0xFF55FF), hex(0x55FFFF), hex(0xFFFFFF),
+	},
+	Foreground: hex(0xAAAAAA),
+	Background: hex(0x000000),
+	Cursor:     hex(0xAAAAAA),
+}
+
+// TerminalApp is the colour scheme of the Basic profile of macOS Terminal.app.
+var TerminalApp = Palette{
+	Name: "Terminal.app",
+	Colours: [16]color.RGBA{
+		hex(0x000000), hex(0xC23621), hex(0x25BC24), hex(0xADAD27),
+		hex(0x492EE1), hex(0xD338D3), hex(0x33BBC8), hex(0xCBCCCD),
+		hex(0x818383), hex(0xFC391F), hex(0x31E722), hex(0xEAEC23),
+		hex(0x5833FF), hex(0xF935F8), hex(0x14F0F0), hex(0xE9EBEB),
+	},
+	Foreground: hex(0x000000),
+	Background: hex(0xFFFFFF),
+	Cursor:     hex(0x929292),
+}
+
+// Ubuntu is the colour scheme of the GNOME Terminal in Ubuntu.
+var Ubuntu = Palette{
+	Name: "Ubuntu",
+	Colours: [16]color.RGBA{
+		hex(0x010101), hex(0xDE382B), hex(0x39B54A), hex(0xFFC706),
+		hex(0x006FB8), hex(0x762671), hex(0x2CB5E9), hex(0xCCCCCC),
+		hex(0x808080), hex(0xFF0000), hex(0x00FF00), hex(0xFFFF00),
+		hex(0x0000FF), hex(0xFF00FF), hex(0x00FFFF), hex(0xFFFFFF),
+	},
+	Foreground: hex(0xFFFFFF),
+	Background: hex(0x300A24),
+	Cursor:     hex(0xFFFFFF),
+}
+
+// solarized are the colours shared by both Solarized schemes.
+var solarized = [16]color.RGBA{
+	hex(0x073642), hex(0xDC322F), hex(0x859900), hex(0xB58900),
+	hex(0x268BD2), hex(0xD33682), hex(0x2AA198), hex(0xEEE8D5),
+	hex(0x002B36), hex(0xCB4B16), hex(0x586E75), hex(0x657B83),
+	hex(0x839496), hex(0x6C71C4), hex(0x93A1A1), hex(0xFDF6E3),
+}
+
+// SolarizedDark is the dark version of Ethan Schoonover's Solarized colour scheme.
+var SolarizedDark = Palette{
+	Name:       "Solarized Dark",
+	Colours:    solarized,
+	Foreground: hex(0x839496),
+	Background: hex(0x002B36),
+	Cursor:     hex(0x93A1A1),
+}
+
+// SolarizedLight is the light version of Ethan Schoonover's Solarized colour scheme.
+var SolarizedLight = Palette{
+	Name:       "Solarized Light",
+	Colours:    solarized,
+	Foreground: hex(0x657B83),
+	Background: hex(0xFDF6E3),
+	Cursor:     hex(0x586E75),
+}
+
+// Tango is the colour scheme of the Tango Desktop Project, as shipped with GNOME Terminal.
+var Tango = Palette{
+	Name: "Tango",
+	Colours: [16]color.RGBA{
+		hex(0x2E3436), hex(0xCC0000), hex(0x4E9A06), hex(0xC4A000),
+		hex(0x3465A4), hex(0x75507B), hex(0x06989A), hex(0xD3D7CF),
+		hex(0x555753), hex(0xEF2929), hex(0x8AE234), hex(0xFCE94F),
+		hex(0x729FCF), hex(0xAD7FA8), hex(0x34E2E2), hex(0xEEEEEC),
+	},
+	Foreground: hex(0xD3D7CF),
+	Background: hex(0x2E3436),
+	Cursor:     hex(0xD3D7CF),
+}
+
+// Dracula is the Dracula colour scheme.
+var Dracula = Palette{
+	Name: "Dracula",
+	Colours: [16]color.RGBA{
+		hex(0x21222C), hex(0xFF5555), hex(0x50FA7B), hex(0xF1FA8C),
+		hex(0xBD93F9), hex(0xFF79C6), hex(0x8BE9FD), hex(0xF8F8F2),
+		hex(0x6272A4), hex(0xFF6E6E), hex(0x69FF94), hex(0xFFFFA5),
+		hex(0xD6ACFF), hex(0xFF92DF), hex(0xA4FFFF), hex(0xFFFFFF),
+	},
+	Foreground: hex(0xF8F8F2),
+	Background: hex(0x282A36),
+	Cursor:     hex(0xF8F8F2),
+}
+
+// Palettes lists every built-in palette.
+var Palettes = []*Palette{
+	&Campbell, &XTerm, &VGA, &TerminalApp, &Ubuntu, &SolarizedDark, &SolarizedLight, &Tango, &Dracula,
+}
+
+// PaletteByName returns the built-in palette with the given name, or nil if there isn't one.
+func PaletteByName(name string) *Palette {
+	for _, p := range Palettes {
+		if p.Name == name {
+			return p
+		}
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package ansi
+
+import (
+	"image/color"
+	"testing"
+)
+
+func TestPalette_Colour(t *testing.T) {
+	var tests = []struct {
+		colour Colour
+		want   color.RGBA
+	}{
+		{colour: RED, want: Tango.Colours[1]},
+		{colour: RED + BackgroundOffset, want: Tango.Colours[1]},
+		{colour: RED + HighIntensityOffset, want: Tango.Colours[9]},
+		{colour: WHITE + BackgroundOffset + HighIntensityOffset, want: Tango.Colours[15]},
+		{colour: BOLD, want: color.RGBA{A: 255}},
+		{colour: FRAMED, want: color.RGBA{A: 255}},
+	}
+
+	for _, test := range tests {
+		if got := Tango.Colour(test.colour); got != test.want {
+			t.Fatalf("Colour %s resolved to %v instead of %v", test.colour, got, test.want)
+		}
+	}
+}
+
+func TestPalette_Resolve(t *testing.T) {
+	t.Run(
+		"Base colours", func(t *testing.T) {
+			if Dracula.Resolve(GREEN) != hex(0x50FA7B) {
+				t.Fatal("Colour wasn't resolved through the palette")
+			}
+			if Dracula.Resolve(Indexed(10)) != hex(0x69FF94) {
+				t.Fatal("Indexed system colour wasn't resolved through the palette")
+			}
+		},
+	)
+
+	t.Run(
+		"Palette independent colours", func(t *testing.T) {
+			if Dracula.Resolve(Indexed(208)) != hex(0xFF8700) {
+				t.Fatal("Indexed cube colour changed with the palette")
+			}
+			var rgb = color.RGBA{R: 1, G: 2, B: 3, A: 255}
+			if Dracula.Resolve(rgb) != rgb {
+				t.Fatal("RGB colour changed with the palette")
+			}
+			if Dracula.Resolve(color.Gray{Y: 128}) != hex(0x808080) {
+				t.Fatal("Other colour models weren't converted")
+			}
+		},
+	)
+
+	t.Run(
+		"Default colour", func(t *testing.T) {
+			if Dracula.Resolve(nil) != Dracula.Foreground {
+				t.Fatal("Nil colour didn't resolve to the default foreground")
+			}
+		},
+	)
+}
+
+func TestPalette_Defaults(t *testing.T) {
+	for testColour := BLACK; testColour <= WHITE; testColour++ {
+		var r, g, b, a = testColour.RGBA8()
+		if Campbell.Colour(testColour) != (color.RGBA{R: uint8(r), G: uint8(g), B: uint8(b), A: uint8(a)}) {
+			t.Fatal("Colour.RGBA8 doesn't match the Campbell palette")
+		}
+	}
+	for i := Indexed(0); i < 16; i++ {
+		var r, g, b, a = i.RGBA8()
+		if XTerm.Colours[i] != (color.RGBA{R: uint8(r), G: uint8(g), B: uint8(b), A: uint8(a)}) {
+			t.Fatal("Indexed.RGBA8 doesn't match the xterm palette")
+		}
+	}
+	if r, g, b, _ := (RED + HighIntensityOffset).RGBA8(); r != 231 || g != 72 || b != 86 {
+		t.Fatal("Bright colour doesn't match the Campbell palette")
+	}
+}
+
+func TestPaletteByName(t *testing.T) {
+	for _, p := range Palettes {
+		if PaletteByName(p.Name) != p {
+			t.Fatalf("Palette %s wasn't found by name", p.Name)
+		}
+		if p.Colours[0].A != 255 || p.Foreground.A != 255 || p.Background.A != 255 || p.Cursor.A != 255 {
+			t.Fatalf("Palette %s has transparent colours", p.Name)
+		}
+	}
+	if PaletteByName("Nonexistent") != nil {
+		t.Fatal("Unknown palette was found")
+	}
+}
@@ -0,0 +1,128 @@
+package ansi
+
+import "errors"
+
+const (
+	SubSeparator = ':' // 58
+	MaxParameter = 65535
+)
+
+// ErrInvalidParameter indicates that a parameter is empty or not a number.
+var ErrInvalidParameter = errors.New("invalid parameter")
+
+// ErrParameterOverflow indicates that a parameter is above ansi.MaxParameter.
+var ErrParameterOverflow = errors.New("parameter value too large")
+
+// Parameter is a single numeric parameter of an ANSI sequence.
+// Parameters separated by ansi.SubSeparator are sub-parameters of the closest parameter before them,
+// as described in ITU T.416.
+type Parameter struct {
+	Value int
+
+	// Sub reports whether the parameter is a sub-parameter of the previous one.
+	Sub bool
+	// Empty reports whether the parameter was omitted, as in 38:2::255:128:0.
+	// Empty parameters have a Value of 0.
+	Empty bool
+}
+
+// ParseParameters will parse the parameters from an ANSI sequence, keeping sub-parameters in order.
+// Only sub-parameters may be empty.
+// Errors are returned as a *ParseError.
+// It is meant to be used along with ansi.ScanCodes and ansi.IsSequence.
+func ParseParameters(sequence []byte) (res []Parameter, err error) {
+	return Strict.ParseParameters(sequence)
+}
+
+// ParseParameters will parse the parameters from an ANSI sequence, keeping sub-parameters in order.
+// Sequences that start with the 8-bit CSI introducer are only accepted in the C1 modes.
+// In Lenient mode, empty parameters are allowed, values above ansi.MaxParameter are clamped,
+// and parameters after the first ansi.MaxParameters are dropped.
+// Errors are returned as a *ParseError.
+func (m ParseMode) ParseParameters(sequence []byte) (res []Parameter, err error) {
+	return m.AppendParameters(nil, sequence)
+}
+
+// AppendParameters will parse the parameters from an ANSI sequence and append them to dst, the same as ansi.ParseParameters.
+// Parsing doesn't allocate unless dst runs out of capacity or the sequence is invalid,
+// so a fixed-size array can be used as the buffer:
+//
+//	var buffer [ansi.MaxParameters]ansi.Parameter
+//	var params, err = ansi.AppendParameters(buffer[:0], sequence)
+func AppendParameters(dst []Parameter, sequence []byte) (res []Parameter, err error) {
+	return Strict.AppendParameters(dst, sequence)
+}
+
+// AppendParameters will parse the parameters from an ANSI sequence and append them to dst, the same as ParseParameters.
+func (m ParseMode) AppendParameters(dst []Parameter, sequence []byte) (res []Parameter, err error) {
+	res = dst
+
+	var start int
+	start, err = checkSequence(sequence, m.c1())
+	if err != nil {
+		return
+	}
+
+	var body = sequence[start : len(sequence)-1]
+	var param = Parameter{Empty: true}
+	var paramStart int
+
+	for i := 0; i <= len(body); i++ {
+		if i == len(body) || body[i] == Separator || body[i] == SubSeparator {
+			if param.Empty && !param.Sub && !m.lenient() {
+				err = newParseError(sequence, start+i, len(res)-len(dst), CategoryNonNumeric, ErrInvalidParameter)
+				return
+			}
+
+			if !m.lenient() || len(res)-len(dst) < MaxParameters {
+				res = append(res, param)
+			}
+			param = Parameter{Empty: true, Sub: i < len(body) && body[i] == SubSeparator}
+			paramStart = i + 1
+			continue
+		}
+
+		if body[i] < '0' || body[i] > '9' {
+			err = newParseError(sequence, start+i, len(res)-len(dst), CategoryNonNumeric, ErrInvalidParameter)
+			return
+		}
+
+		param.Value = param.Value*10 + int(body[i]-'0')
+		param.Empty = false
+
+		if param.Value > MaxParameter {
+			if m.lenient() {
+				param.Value = MaxParameter
+				continue
+			}
+
+			err = newParseError(sequence, start+paramStart, len(res)-len(dst), CategoryOverflow, ErrParameterOverflow)
+			return
+		}
+	}
+
+	return
+}
+
+// checkSequence checks that sequence is a complete control sequence, and returns the length of its introducer.
+// The 8-bit CSI introducer is only accepted if c1 is set.
+func checkSequence(sequence []byte, c1 bool) (start int, err error) {
+	var code byte
+	code, start = sequenceStart(sequence, c1)
+
+	switch {
+	case start == 0 || code != StartCode:
+		err = newParseError(sequence, 0, -1, CategoryMalformed, ErrNotSequence)
+	case len(sequence) == start || !isFinalByte(sequence[len(sequence)-1]):
+		err = newParseError(sequence, len(sequence)-1, -1, CategoryUnterminated, ErrUnterminated)
+	}
+
+	return
+}
+
+// groupLength returns how many parameters belong to the first parameter, including itself.
+func groupLength(params []Parameter) (n int) {
+	for n = 1; n < len(params) && params[n].Sub; n++ {
+	}
+	return
+}
@@ -0,0 +1,103 @@
+package ansi
+
+import (
+	"errors"
+	"testing"
+)
+
+func Test_ParseParameters(t *testing.T) {
+	t.Run(
+		"Separated", func(t *testing.T) {
+			var (
+				expected = []Parameter{
+					{Value: 38},
+					{Value: 2, Sub: true},
+					{Sub: true, Empty: true},
+					{Value: 255, Sub: true},
+					{Value: 128, Sub: true},
+					{Value: 0, Sub: true},
+					{Value: 4},
+					{Value: 3, Sub: true},
+					{Value: 1},
+				}
+				actual, err = ParseParameters([]byte("\x1b[38:2::255:128:0;4:3;1m"))
+			)
+
+			if err != nil {
+				t.Fatal("Errored while parsing parameters")
+			}
+
+			if len(actual) != len(expected) {
+				t.Fatal("Wrong number of parameters")
+			}
+
+			for i := 0; i < len(expected); i++ {
+				if expected[i] != actual[i] {
+					t.Fatalf("Parameter %d parsed incorrectly", i)
+				}
+			}
+		},
+	)
+
+	t.Run(
+		"Invalid", func(t *testing.T) {
+			var invalid = []string{"\x1b[m", "\x1b[;1m", "\x1b[1;m", "\x1b[1;am", "\x1b[1;-1m"}
+			for _, sequence := range invalid {
+				var _, err = ParseParameters([]byte(sequence))
+				if !errors.Is(err, ErrInvalidParameter) {
+					t.Fatalf("Sequence %q should be invalid", sequence)
+				}
+			}
+
+			var _, err = ParseParameters([]byte("\x1b[65536m"))
+			if !errors.Is(err, ErrParameterOverflow) {
+				t.Fatal("Parameter overflow isn't being detected")
+			}
+		},
+	)
+}
+
+func Test_groupLength(t *testing.T) {
+	var params = []Parameter{{Value: 4}, {Value: 3, Sub: true}, {Value: 1}}
+	if groupLength(params) != 2 {
+		t.Fatal("Sub-parameters aren't part of the group")
+	}
+	if groupLength(params[2:]) != 1 {
+		t.Fatal("Single parameter should be its own group")
+	}
+}
+
+func Test_AppendParameters(t *testing.T) {
+	var sequence = []byte("\x1b[0;1;38:2::255:128:0;48;5;21m")
+	var buffer [MaxParameters]Parameter
+
+	var allocs = testing.AllocsPerRun(
+		100, func() {
+			var _, err = AppendParameters(buffer[:0], sequence)
+			if err != nil {
+				panic(err)
+			}
+		},
+	)
+
+	if allocs != 0 {
+		t.Fatalf("Parsing into a buffer shouldn't allocate, got %v allocations", allocs)
+	}
+
+	var expected, _ = ParseParameters(sequence)
+	var actual, _ = AppendParameters(buffer[:0], sequence)
+	if len(actual) != len(expected) {
+		t.Fatal("Parsing into a buffer should match ParseParameters")
+	}
+
+	for i := range expected {
+		if actual[i] != expected[i] {
+			t.Fatal("Parsing into a buffer should match ParseParameters")
+		}
+	}
+
+	actual, _ = AppendParameters(actual, []byte("\x1b[4m"))
+	if len(actual) != len(expected)+1 || actual[len(actual)-1].Value != 4 {
+		t.Fatal("Parameters should be appended after the existing ones")
+	}
+}
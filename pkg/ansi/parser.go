@@ -0,0 +1,436 @@
+package ansi
+
+import "unicode/utf8"
+
+const (
+	// MaxParameters is the number of parameters the Parser keeps for a single sequence.
+	// Any parameters after it are ignored.
+	MaxParameters = 32
+	// MaxIntermediates is the number of intermediate bytes the Parser keeps for a single sequence.
+	// Sequences with more are ignored.
+	MaxIntermediates = 2
+	// MaxStringLength is the number of bytes the Parser keeps from an OSC string.
+	// Any bytes after it are dropped.
+	MaxStringLength = 1 << 16
+)
+
+const (
+	cancelCode     = 0x18
+	substituteCode = 0x1A
+	deleteCode     = 0x7F
+)
+
+// Handler receives the actions of a Parser.
+// Slices given to the handler are only valid until the method returns.
+type Handler interface {
+	// Print is called for every printable character.
+	// Invalid UTF-8 is printed as utf8.RuneError.
+	Print(r rune)
+	// Execute is called for C0 control characters, such as line feeds and bells.
+	Execute(b byte)
+	// CsiDispatch is called for every complete control sequence.
+	// Private markers, such as the ? in ESC[?25h, are included in the intermediates.
+	CsiDispatch(params []Parameter, intermediates []byte, final byte)
+	// EscDispatch is called for every complete escape sequence that isn't a control sequence or string.
+	EscDispatch(intermediates []byte, final byte)
+	// OscDispatch is called with the data of every complete OSC string.
+	OscDispatch(data []byte)
+	// Hook is called when a device control string starts, with the parameters of its header.
+	Hook(params []Parameter, intermediates []byte, final byte)
+	// Put is called for every byte of the data of a device control string.
+	Put(b byte)
+	// Unhook is called when a device control string ends.
+	Unhook()
+}
+
+// parserState is a state of the DEC ANSI parser state diagram.
+type parserState byte
+
+const (
+	stateGround parserState = iota
+	stateEscape
+	stateEscapeIntermediate
+	stateCsiEntry
+	stateCsiParam
+	stateCsiIntermediate
+	stateCsiIgnore
+	stateDcsEntry
+	stateDcsParam
+	stateDcsIntermediate
+	stateDcsPassthrough
+	stateDcsIgnore
+	stateOscString
+	stateSosPmApcString
+)
+
+// Parser is a byte at a time parser modelled on the DEC ANSI parser state diagram,
+// as documented in https://vt100.net/emu/dec_ansi_parser.
+// It calls a Handler for every action, and can be written to in arbitrary chunks.
+//
+// Colons are accepted as sub-parameter separators, following ITU T.416,
+// and string terminators (ESC \) that end a control string aren't dispatched as escape sequences.
+type Parser struct {
+	// C1 enables 8-bit C1 controls, such as CSI (0x9B), OSC (0x9D) and ST (0x9C).
+	// Printed text and control strings are read as UTF-8, so bytes that are part of a valid UTF-8 character
+	// are never treated as C1 controls.
+	C1 bool
+
+	handler Handler
+	state   parserState
+
+	params        []Parameter
+	param         Parameter
+	hasParam      bool
+	intermediates []byte
+	ignoring      bool
+	osc           []byte
+
+	// UTF-8 character being printed or read in a control string
+	rune    [utf8.UTFMax]byte
+	runeLen int
+	runeAt  int
+
+	// Whether the last escape ended a control string
+	terminating bool
+}
+
+// NewParser creates a parser in the ground state that calls the given handler.
+func NewParser(handler Handler) *Parser {
+	return &Parser{
+		handler:       handler,
+		params:        make([]Parameter, 0, MaxParameters),
+		intermediates: make([]byte, 0, MaxIntermediates),
+	}
+}
+
+// Write parses data, calling the handler for every action.
+// Sequences can be split across writes.
+// This function implements io.Writer and never fails.
+func (p *Parser) Write(data []byte) (n int, err error) {
+	for _, b := range data {
+		p.advance(b)
+	}
+	return len(data), nil
+}
+
+// Reset returns the parser to the ground state, discarding any partial sequence.
+// The C1 setting is kept.
+func (p *Parser) Reset() {
+	p.state = stateGround
+	p.runeAt = 0
+	p.terminating = false
+	p.clear()
+}
+
+// advance runs a single byte through the state machine.
+func (p *Parser) advance(b byte) {
+	if p.runeAt > 0 {
+		if b&0xC0 == 0x80 {
+			p.continueRune(b)
+			return
+		}
+		p.runeAt = 0
+		if p.state == stateGround {
+			p.handler.Print(utf8.RuneError)
+		}
+	}
+
+	if p.C1 && isC1(b) {
+		p.control(b)
+		return
+	}
+
+	// Transitions from anywhere
+	switch b {
+	case cancelCode, substituteCode:
+		p.exit()
+		p.handler.Execute(b)
+		p.state = stateGround
+		return
+	case EscapeCode:
+		var terminating = p.state == stateOscString || p.state == stateDcsPassthrough || p.state == stateSosPmApcString
+		p.exit()
+		p.enter(stateEscape)
+		p.terminating = terminating
+		return
+	}
+
+	var terminating = p.terminating
+	p.terminating = false
+
+	switch p.state {
+	case stateGround:
+		switch {
+		case b < 0x20:
+			p.handler.Execute(b)
+		case b < deleteCode:
+			p.handler.Print(rune(b))
+		case b >= 0x80:
+			p.printLead(b)
+		}
+
+	case stateEscape:
+		switch {
+		case b < 0x20:
+			p.handler.Execute(b)
+		case isIntermediateByte(b):
+			p.collect(b)
+			p.state = stateEscapeIntermediate
+		case b == StartCode:
+			p.enter(stateCsiEntry)
+		case b == OSCCode:
+			p.enter(stateOscString)
+		case b == DCSCode:
+			p.enter(stateDcsEntry)
+		case b == SOSCode || b == PMCode || b == APCCode:
+			p.enter(stateSosPmApcString)
+		case b == StringTerminatorCode && terminating:
+			p.state = stateGround
+		case b < deleteCode:
+			p.handler.EscDispatch(p.intermediates, b)
+			p.state = stateGround
+		}
+
+	case stateEscapeIntermediate:
+		switch {
+		case b < 0x20:
+			p.handler.Execute(b)
+		case isIntermediateByte(b):
+			p.collect(b)
+		case b < deleteCode:
+			if !p.ignoring {
+				p.handler.EscDispatch(p.intermediates, b)
+			}
+			p.state = stateGround
+		}
+
+	case stateCsiEntry, stateCsiParam, stateCsiIntermediate:
+		switch {
+		case b < 0x20:
+			p.handler.Execute(b)
+		case b < deleteCode:
+			p.state = p.sequence(b, stateCsiParam, stateCsiIntermediate, stateCsiIgnore)
+			if p.state == stateGround && !p.ignoring {
+				p.handler.CsiDispatch(p.finishParams(), p.intermediates, b)
+			}
+		case b > deleteCode:
+			p.state = stateCsiIgnore
+		}
+
+	case stateCsiIgnore:
+		switch {
+		case b < 0x20:
+			p.handler.Execute(b)
+		case isFinalByte(b):
+			p.state = stateGround
+		}
+
+	case stateDcsEntry, stateDcsParam, stateDcsIntermediate:
+		if b >= 0x20 && b < deleteCode {
+			p.state = p.sequence(b, stateDcsParam, stateDcsIntermediate, stateDcsIgnore)
+			if p.state == stateGround {
+				p.state = stateDcsIgnore
+				if !p.ignoring {
+					p.state = stateDcsPassthrough
+					p.handler.Hook(p.finishParams(), p.intermediates, b)
+				}
+			}
+		} else if b >= 0x80 {
+			p.state = stateDcsIgnore
+		}
+
+	case stateDcsPassthrough:
+		if b != deleteCode {
+			p.handler.Put(b)
+		}
+		if p.C1 && b >= 0xC0 {
+			p.startRune(b)
+		}
+
+	case stateSosPmApcString:
+		if p.C1 && b >= 0xC0 {
+			p.startRune(b)
+		}
+
+	case stateOscString:
+		switch {
+		case b == BellCode:
+			p.exit()
+			p.state = stateGround
+		case b >= 0x20:
+			if len(p.osc) < MaxStringLength {
+				p.osc = append(p.osc, b)
+			}
+			if p.C1 && b >= 0xC0 {
+				p.startRune(b)
+			}
+		}
+	}
+}
+
+// control handles an 8-bit C1 control, which can happen from any state.
+func (p *Parser) control(b byte) {
+	p.exit()
+
+	switch b - C1Offset {
+	case StartCode:
+		p.enter(stateCsiEntry)
+	case OSCCode:
+		p.enter(stateOscString)
+	case DCSCode:
+		p.enter(stateDcsEntry)
+	case SOSCode, PMCode, APCCode:
+		p.enter(stateSosPmApcString)
+	case StringTerminatorCode:
+		p.state = stateGround
+	default:
+		p.handler.Execute(b)
+		p.state = stateGround
+	}
+}
+
+// sequence handles a byte in the entry, parameter or intermediate states of a control sequence or device control string.
+// Returns the next state, or the ground state if b is the final byte.
+func (p *Parser) sequence(b byte, param, intermediate, ignore parserState) parserState {
+	var entry = p.state != param && p.state != intermediate
+
+	switch {
+	case isIntermediateByte(b):
+		p.collect(b)
+		return intermediate
+	case b >= '<' && b <= '?':
+		// Private markers are only valid before any parameter
+		if !entry {
+			return ignore
+		}
+		p.collect(b)
+		return param
+	case isParameterByte(b):
+		if p.state == intermediate {
+			return ignore
+		}
+		p.addParam(b)
+		return param
+	default:
+		return stateGround
+	}
+}
+
+// enter clears the sequence buffers and moves to the given state.
+func (p *Parser) enter(state parserState) {
+	p.clear()
+	p.state = state
+}
+
+// exit runs the exit action of the current state.
+func (p *Parser) exit() {
+	switch p.state {
+	case stateOscString:
+		p.handler.OscDispatch(p.osc)
+	case stateDcsPassthrough:
+		p.handler.Unhook()
+	}
+}
+
+// clear discards any collected parameters, intermediates and string data.
+func (p *Parser) clear() {
+	p.params = p.params[:0]
+	p.param = Parameter{Empty: true}
+	p.hasParam = false
+	p.intermediates = p.intermediates[:0]
+	p.ignoring = false
+	p.osc = p.osc[:0]
+}
+
+// collect stores an intermediate byte, ignoring the sequence if there are too many.
+func (p *Parser) collect(b byte) {
+	if len(p.intermediates) == MaxIntermediates {
+		p.ignoring = true
+		return
+	}
+	p.intermediates = append(p.intermediates, b)
+}
+
+// addParam updates the parameters with a digit or separator.
+// Values are clamped to ansi.MaxParameter.
+func (p *Parser) addParam(b byte) {
+	p.hasParam = true
+
+	switch b {
+	case Separator, SubSeparator:
+		p.pushParam()
+		p.param = Parameter{Empty: true, Sub: b == SubSeparator}
+	default:
+		p.param.Value = min(p.param.Value*10+int(b-'0'), MaxParameter)
+		p.param.Empty = false
+	}
+}
+
+// pushParam stores the current parameter, dropping it if there are too many.
+func (p *Parser) pushParam() {
+	if len(p.params) < MaxParameters {
+		p.params = append(p.params, p.param)
+	}
+}
+
+// finishParams stores the last parameter and returns all of them.
+func (p *Parser) finishParams() []Parameter {
+	if p.hasParam {
+		p.pushParam()
+	}
+	return p.params
+}
+
+// printLead starts printing a multi-byte UTF-8 character.
+func (p *Parser) printLead(b byte) {
+	if !p.startRune(b) {
+		p.handler.Print(utf8.RuneError)
+	}
+}
+
+// startRune starts reading a multi-byte UTF-8 character.
+// Returns false if b can't start one.
+func (p *Parser) startRune(b byte) bool {
+	switch {
+	case b&0xE0 == 0xC0:
+		p.runeLen = 2
+	case b&0xF0 == 0xE0:
+		p.runeLen = 3
+	case b&0xF8 == 0xF0:
+		p.runeLen = 4
+	default:
+		return false
+	}
+
+	p.rune[0] = b
+	p.runeAt = 1
+	return true
+}
+
+// continueRune continues reading a multi-byte UTF-8 character, printing it once complete if in the ground state.
+// In a control string the byte is added to the string data instead.
+func (p *Parser) continueRune(b byte) {
+	p.rune[p.runeAt] = b
+	p.runeAt++
+
+	if p.state != stateGround {
+		switch p.state {
+		case stateOscString:
+			if len(p.osc) < MaxStringLength {
+				p.osc = append(p.osc, b)
+			}
+		case stateDcsPassthrough:
+			p.handler.Put(b)
+		}
+		if p.runeAt == p.runeLen {
+			p.runeAt = 0
+		}
+		return
+	}
+
+	if p.runeAt == p.runeLen {
+		var r, _ = utf8.DecodeRune(p.rune[:p.runeLen])
+		p.runeAt = 0
+		p.handler.Print(r)
+	}
+}
@@ -0,0 +1,251 @@
+package ansi
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// recorder is a Handler that records every action as a string.
+type recorder struct {
+	events []string
+	text   strings.Builder
+}
+
+func (r *recorder) flush() {
+	if r.text.Len() > 0 {
+		r.events = append(r.events, "print "+r.text.String())
+		r.text.Reset()
+	}
+}
+
+func (r *recorder) add(format string, args ...any) {
+	r.flush()
+	r.events = append(r.events, fmt.Sprintf(format, args...))
+}
+
+func (r *recorder) Print(c rune)   { r.text.WriteRune(c) }
+func (r *recorder) Execute(b byte) { r.add("execute %02x", b) }
+func (r *recorder) Put(b byte)     { r.add("put %c", b) }
+func (r *recorder) Unhook()        { r.add("unhook") }
+
+func (r *recorder) CsiDispatch(params []Parameter, intermediates []byte, final byte) {
+	r.add("csi %v %q %c", formatParameters(params), intermediates, final)
+}
+
+func (r *recorder) EscDispatch(intermediates []byte, final byte) {
+	r.add("esc %q %c", intermediates, final)
+}
+
+func (r *recorder) OscDispatch(data []byte) {
+	r.add("osc %q", data)
+}
+
+func (r *recorder) Hook(params []Parameter, intermediates []byte, final byte) {
+	r.add("hook %v %q %c", formatParameters(params), intermediates, final)
+}
+
+// formatParameters writes parameters the same way they are written in a sequence, with empty ones as _.
+func formatParameters(params []Parameter) string {
+	var res strings.Builder
+	for i, param := range params {
+		if i > 0 && param.Sub {
+			res.WriteByte(SubSeparator)
+		} else if i > 0 {
+			res.WriteByte(Separator)
+		}
+
+		if param.Empty {
+			res.WriteByte('_')
+		} else {
+			res.WriteString(fmt.Sprint(param.Value))
+		}
+	}
+	return "[" + res.String() + "]"
+}
+
+func parseEvents(chunks ...string) []string {
+	return parseEventsMode(false, chunks...)
+}
+
+func parseEventsMode(c1 bool, chunks ...string) []string {
+	var handler = &recorder{}
+	var parser = NewParser(handler)
+	parser.C1 = c1
+
+	for _, chunk := range chunks {
+		_, _ = parser.Write([]byte(chunk))
+	}
+
+	handler.flush()
+	return handler.events
+}
+
+func TestParser(t *testing.T) {
+	var tests = []struct {
+		name     string
+		input    string
+		expected []string
+	}{
+		{
+			name:     "Text",
+			input:    "Hello, 世界!\r\n",
+			expected: []string{"print Hello, 世界!", "execute 0d", "execute 0a"},
+		},
+		{
+			name:  "CSI",
+			input: "\x1b[1;31mred\x1b[m\x1b[?25l\x1b[;5H\x1b[38:2::1:2:3m\x1b[2 q",
+			expected: []string{
+				`csi [1;31] "" m`, "print red", `csi [] "" m`, `csi [25] "?" l`, `csi [_;5] "" H`,
+				`csi [38:2:_:1:2:3] "" m`, `csi [2] " " q`,
+			},
+		},
+		{
+			name:     "CSI with control",
+			input:    "\x1b[3\n1m",
+			expected: []string{"execute 0a", `csi [31] "" m`},
+		},
+		{
+			name:     "Ignored CSI",
+			input:    "\x1b[1?mok\x1b[1 2mok",
+			expected: []string{"print okok"},
+		},
+		{
+			name:     "Overflow",
+			input:    "\x1b[99999999m",
+			expected: []string{`csi [65535] "" m`},
+		},
+		{
+			name:     "ESC",
+			input:    "\x1b7\x1b(B\x1bc",
+			expected: []string{`esc "" 7`, `esc "(" B`, `esc "" c`},
+		},
+		{
+			name:  "OSC",
+			input: "\x1b]0;title\x07\x1b]8;;http://a\x1b\\link",
+			expected: []string{
+				`osc "0;title"`, `osc "8;;http://a"`, "print link",
+			},
+		},
+		{
+			name:  "DCS",
+			input: "\x1bP1$qm\x1b\\\x1bP+q54\x07\x1b\\",
+			expected: []string{
+				`hook [1] "$" q`, "put m", "unhook", `hook [] "+" q`, "put 5", "put 4", "put \a", "unhook",
+			},
+		},
+		{
+			name:     "SOS PM APC",
+			input:    "\x1bXsos\x1b\\\x1b^pm\x1b\\\x1b_apc\x1b\\text",
+			expected: []string{"print text"},
+		},
+		{
+			name:     "Cancel",
+			input:    "\x1b[31\x18m\x1b]0;ti\x1atle",
+			expected: []string{"execute 18", "print m", `osc "0;ti"`, "execute 1a", "print tle"},
+		},
+		{
+			name:     "Invalid UTF-8",
+			input:    "a\xffb\xe4\xb8c\x80",
+			expected: []string{"print a�b�c�"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.name, func(t *testing.T) {
+				var actual = parseEvents(test.input)
+				if strings.Join(actual, "\n") != strings.Join(test.expected, "\n") {
+					t.Fatalf("Expected %q, got %q", test.expected, actual)
+				}
+
+				// Every byte on its own must produce the same actions
+				var chunks []string
+				for i := 0; i < len(test.input); i++ {
+					chunks = append(chunks, test.input[i:i+1])
+				}
+
+				actual = parseEvents(chunks...)
+				if strings.Join(actual, "\n") != strings.Join(test.expected, "\n") {
+					t.Fatalf("Split input expected %q, got %q", test.expected, actual)
+				}
+			},
+		)
+	}
+}
+
+func TestParser_Reset(t *testing.T) {
+	var handler = &recorder{}
+	var parser = NewParser(handler)
+
+	_, _ = parser.Write([]byte("\x1b[31"))
+	parser.Reset()
+	_, _ = parser.Write([]byte("m"))
+	handler.flush()
+
+	if len(handler.events) != 1 || handler.events[0] != "print m" {
+		t.Fatalf("Reset should discard the partial sequence, got %q", handler.events)
+	}
+}
+
+func TestParser_C1(t *testing.T) {
+	var tests = []struct {
+		name     string
+		input    string
+		expected []string
+	}{
+		{
+			name:  "Introducers",
+			input: "\x9b31mred\x9d0;title\x9c\x90+q\x9c\x98sos\x9c\x85",
+			expected: []string{
+				`csi [31] "" m`, "print red", `osc "0;title"`, `hook [] "+" q`, "unhook", "execute 85",
+			},
+		},
+		{
+			name:  "UTF-8",
+			input: "děkuji\x9b1m\x9d0;ŜĜ\x9c",
+			expected: []string{
+				"print děkuji", `csi [1] "" m`, `osc "0;ŜĜ"`,
+			},
+		},
+		{
+			// Ĝ is C4 9C, which contains the string terminator byte
+			name:  "UTF-8 in strings",
+			input: "\x90qĜ\x9c\x9eĜ\x9cok",
+			expected: []string{
+				`hook [] "" q`, "put \u00c4", "put \u009c", "unhook", "print ok",
+			},
+		},
+		{
+			name:     "Broken UTF-8",
+			input:    "\xc4A\x9b1m",
+			expected: []string{"print \ufffdA", `csi [1] "" m`},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.name, func(t *testing.T) {
+				var actual = parseEventsMode(true, test.input)
+				if strings.Join(actual, "\n") != strings.Join(test.expected, "\n") {
+					t.Fatalf("Expected %q, got %q", test.expected, actual)
+				}
+
+				var chunks []string
+				for i := 0; i < len(test.input); i++ {
+					chunks = append(chunks, test.input[i:i+1])
+				}
+
+				actual = parseEventsMode(true, chunks...)
+				if strings.Join(actual, "\n") != strings.Join(test.expected, "\n") {
+					t.Fatalf("Split input expected %q, got %q", test.expected, actual)
+				}
+			},
+		)
+	}
+
+	var actual = parseEvents("\x9b31m")
+	if len(actual) != 1 || actual[0] != "print \ufffd31m" {
+		t.Fatalf("C1 controls should be invalid UTF-8 unless enabled, got %q", actual)
+	}
+}
@@ -0,0 +1,214 @@
+package ansi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image/color"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ErrMissingKey indicates that a colour scheme file doesn't define a required colour.
+var ErrMissingKey = errors.New("missing key")
+
+// ErrInvalidColour indicates that a colour scheme file has a colour that can't be read.
+var ErrInvalidColour = errors.New("invalid colour")
+
+// PaletteError describes a colour scheme that couldn't be loaded and which key is at fault.
+// It wraps the error that caused it, so it can be checked with errors.Is as well as errors.As.
+type PaletteError struct {
+	// Name is the name of the colour scheme, if known.
+	Name string
+	// Key is the key that is missing or invalid.
+	Key string
+	// Line is the line of the file where the key is, or 0 if unknown.
+	Line int
+	Err  error
+}
+
+func (e *PaletteError) Error() string {
+	var key = fmt.Sprintf("key %q", e.Key)
+	if e.Line > 0 {
+		key = fmt.Sprintf("%s on line %d", key, e.Line)
+	}
+	if e.Name == "" {
+		return fmt.Sprintf("ansi: palette %s: %v", key, e.Err)
+	}
+	return fmt.Sprintf("ansi: palette %q %s: %v", e.Name, key, e.Err)
+}
+
+func (e *PaletteError) Unwrap() error {
+	return e.Err
+}
+
+// windowsTerminalKeys are the keys of the 16 base colours in a Windows Terminal colour scheme.
+var windowsTerminalKeys = [16]string{
+	"black", "red", "green", "yellow", "blue", "purple", "cyan", "white",
+	"brightBlack", "brightRed", "brightGreen", "brightYellow", "brightBlue", "brightPurple", "brightCyan", "brightWhite",
+}
+
+// ParseWindowsTerminal reads every colour scheme in a Windows Terminal settings.json file.
+// Comments and trailing commas are allowed, as they are by Windows Terminal.
+// The data can also be a single colour scheme object, as they are usually shared.
+// Schemes without a cursorColor use the foreground colour for the cursor.
+func ParseWindowsTerminal(data []byte) (palettes []*Palette, err error) {
+	var settings struct {
+		Schemes []map[string]any `json:"schemes"`
+	}
+	data = stripJSONC(data)
+	if err = json.Unmarshal(data, &settings); err != nil {
+		return nil, err
+	}
+
+	if settings.Schemes == nil {
+		var scheme map[string]any
+		if err = json.Unmarshal(data, &scheme); err != nil {
+			return nil, err
+		}
+		if _, ok := scheme["name"]; ok {
+			settings.Schemes = append(settings.Schemes, scheme)
+		}
+	}
+
+	palettes = make([]*Palette, 0, len(settings.Schemes))
+	for _, scheme := range settings.Schemes {
+		var p *Palette
+		if p, err = windowsTerminalScheme(scheme); err != nil {
+			return nil, err
+		}
+		palettes = append(palettes, p)
+	}
+
+	return
+}
+
+// LoadWindowsTerminal reads every colour scheme in a Windows Terminal settings.json file from r.
+func LoadWindowsTerminal(r io.Reader) ([]*Palette, error) {
+	var data, err = io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return ParseWindowsTerminal(data)
+}
+
+// windowsTerminalScheme converts a single Windows Terminal colour scheme into a Palette.
+func windowsTerminalScheme(scheme map[string]any) (p *Palette, err error) {
+	p = &Palette{}
+	p.Name, _ = scheme["name"].(string)
+
+	var get = func(key string, dst *color.RGBA) error {
+		var value, ok = scheme[key]
+		if !ok {
+			return &PaletteError{Name: p.Name, Key: key, Err: ErrMissingKey}
+		}
+		var s, _ = value.(string)
+		var c, err = parseHex(s)
+		if err != nil {
+			return &PaletteError{Name: p.Name, Key: key, Err: err}
+		}
+		*dst = c
+		return nil
+	}
+
+	for i, key := range windowsTerminalKeys {
+		if err = get(key, &p.Colours[i]); err != nil {
+			return nil, err
+		}
+	}
+	if err = get("foreground", &p.Foreground); err != nil {
+		return nil, err
+	}
+	if err = get("background", &p.Background); err != nil {
+		return nil, err
+	}
+
+	p.Cursor = p.Foreground
+	if _, ok := scheme["cursorColor"]; ok {
+		if err = get("cursorColor", &p.Cursor); err != nil {
+			return nil, err
+		}
+	}
+
+	return
+}
+
+// parseHex reads a colour written as #RGB or #RRGGBB, with or without the #.
+func parseHex(s string) (c color.RGBA, err error) {
+	var digits = strings.TrimPrefix(s, "#")
+	var rgb, perr = strconv.ParseUint(digits, 16, 32)
+	if perr != nil {
+		return c, ErrInvalidColour
+	}
+
+	switch len(digits) {
+	case 3:
+		c.R, c.G, c.B = uint8(rgb>>8)*0x11, uint8(rgb>>4&0xF)*0x11, uint8(rgb&0xF)*0x11
+	case 6:
+		c.R, c.G, c.B = uint8(rgb>>16), uint8(rgb>>8), uint8(rgb)
+	default:
+		return c, ErrInvalidColour
+	}
+
+	c.A = 255
+	return
+}
+
+// stripJSONC removes comments and trailing commas from JSON, outside of strings.
+func stripJSONC(data []byte) []byte {
+	data = bytes.TrimPrefix(data, []byte("\xEF\xBB\xBF"))
+
+	var out = make([]byte, 0, len(data))
+	var inString, escaped bool
+	var comma = -1
+
+	for i := 0; i < len(data); i++ {
+		var b = data[i]
+
+		if inString {
+			out = append(out, b)
+			switch {
+			case escaped:
+				escaped = false
+			case b == '\\':
+				escaped = true
+			case b == '"':
+				inString = false
+			}
+			continue
+		}
+
+		switch {
+		case b == '/' && i+1 < len(data) && data[i+1] == '/':
+			for i < len(data) && data[i] != '\n' {
+				i++
+			}
+			i--
+		case b == '/' && i+1 < len(data) && data[i+1] == '*':
+			var end = bytes.Index(data[i+2:], []byte("*/"))
+			if end < 0 {
+				return out
+			}
+			i += end + 3
+		case b == ' ' || b == '\t' || b == '\r' || b == '\n':
+			out = append(out, b)
+		case b == ',':
+			comma = len(out)
+			out = append(out, b)
+		case (b == '}' || b == ']') && comma >= 0:
+			out = append(out[:comma], out[comma+1:]...)
+			out = append(out, b)
+			comma = -1
+		default:
+			if b == '"' {
+				inString = true
+			}
+			out = append(out, b)
+			comma = -1
+		}
+	}
+
+	return out
+}
@@ -0,0 +1,154 @@
+package ansi
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+const windowsTerminalSettings = `// This file was initially generated by Windows Terminal
+{
+    "$schema": "https://aka.ms/terminal-profiles-schema",
+    "defaultProfile": "{61c54bbd-c2c6-5271-96e7-009a87ff44bf}",
+    /* Colour schemes shared by the team */
+    "schemes": [
+        {
+            "name": "One Half Dark",
+            "background": "#282C34",
+            "foreground": "#DCDFE4",
+            "cursorColor": "#FFFFFF",
+            "selectionBackground": "#FFFFFF",
+            "black": "#282C34",
+            "red": "#E06C75",
+            "green": "#98C379",
+            "yellow": "#E5C07B",
+            "blue": "#61AFEF",
+            "purple": "#C678DD",
+            "cyan": "#56B6C2",
+            "white": "#DCDFE4",
+            "brightBlack": "#5A6374",
+            "brightRed": "#E06C75",
+            "brightGreen": "#98C379",
+            "brightYellow": "#E5C07B",
+            "brightBlue": "#61AFEF",
+            "brightPurple": "#C678DD",
+            "brightCyan": "#56B6C2",
+            "brightWhite": "#DCDFE4", // trailing comma
+        },
+    ],
+}
+`
+
+// windowsTerminalSchemeWith returns the settings with the given key replaced or, if value is empty, removed.
+func windowsTerminalSchemeWith(key, value string) string {
+	var lines []string
+	for _, line := range strings.Split(windowsTerminalSettings, "\n") {
+		if strings.Contains(line, `"`+key+`"`) {
+			if value == "" {
+				continue
+			}
+			line = `"` + key + `": "` + value + `",`
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func TestParseWindowsTerminal(t *testing.T) {
+	t.Run(
+		"Settings file", func(t *testing.T) {
+			var palettes, err = ParseWindowsTerminal([]byte(windowsTerminalSettings))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(palettes) != 1 {
+				t.Fatalf("Parsed %d palettes instead of 1", len(palettes))
+			}
+
+			var p = palettes[0]
+			if p.Name != "One Half Dark" {
+				t.Fatal("Wrong palette name", p.Name)
+			}
+			if p.Colours[0] != hex(0x282C34) || p.Colours[5] != hex(0xC678DD) || p.Colours[8] != hex(0x5A6374) {
+				t.Fatal("Wrong palette colours", p.Colours)
+			}
+			if p.Foreground != hex(0xDCDFE4) || p.Background != hex(0x282C34) || p.Cursor != hex(0xFFFFFF) {
+				t.Fatal("Wrong default colours")
+			}
+		},
+	)
+
+	t.Run(
+		"Single scheme", func(t *testing.T) {
+			var scheme = `{"name": "Short", "foreground": "#FFF", "background": "#000", ` +
+				`"black": "#000", "red": "#F00", "green": "#0F0", "yellow": "#FF0", ` +
+				`"blue": "#00F", "purple": "#F0F", "cyan": "#0FF", "white": "#FFF", ` +
+				`"brightBlack": "#000", "brightRed": "#F00", "brightGreen": "#0F0", "brightYellow": "#FF0", ` +
+				`"brightBlue": "#00F", "brightPurple": "#F0F", "brightCyan": "#0FF", "brightWhite": "#FFF"}`
+			var palettes, err = ParseWindowsTerminal([]byte(scheme))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(palettes) != 1 || palettes[0].Name != "Short" {
+				t.Fatal("Single scheme wasn't parsed")
+			}
+			if palettes[0].Colours[1] != hex(0xFF0000) {
+				t.Fatal("Short hex colour wasn't expanded")
+			}
+			if palettes[0].Cursor != palettes[0].Foreground {
+				t.Fatal("Cursor didn't default to the foreground")
+			}
+		},
+	)
+
+	t.Run(
+		"Comments in strings", func(t *testing.T) {
+			var settings = windowsTerminalSchemeWith("name", "http://example.com/* not a comment */")
+			var palettes, err = ParseWindowsTerminal([]byte(settings))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if palettes[0].Name != "http://example.com/* not a comment */" {
+				t.Fatal("Comment was stripped from a string", palettes[0].Name)
+			}
+		},
+	)
+
+	t.Run(
+		"Missing key", func(t *testing.T) {
+			var _, err = ParseWindowsTerminal([]byte(windowsTerminalSchemeWith("brightCyan", "")))
+			var paletteErr *PaletteError
+			if !errors.As(err, &paletteErr) || !errors.Is(err, ErrMissingKey) {
+				t.Fatal("Missing key wasn't reported", err)
+			}
+			if paletteErr.Key != "brightCyan" || paletteErr.Name != "One Half Dark" {
+				t.Fatal("Error doesn't point at the missing key", err)
+			}
+		},
+	)
+
+	t.Run(
+		"Invalid colour", func(t *testing.T) {
+			var _, err = ParseWindowsTerminal([]byte(windowsTerminalSchemeWith("red", "#E06C7")))
+			var paletteErr *PaletteError
+			if !errors.As(err, &paletteErr) || !errors.Is(err, ErrInvalidColour) || paletteErr.Key != "red" {
+				t.Fatal("Invalid colour wasn't reported", err)
+			}
+		},
+	)
+
+	t.Run(
+		"Invalid JSON", func(t *testing.T) {
+			if _, err := ParseWindowsTerminal([]byte(`{"schemes": [`)); err == nil {
+				t.Fatal("Invalid JSON was accepted")
+			}
+		},
+	)
+}
+
+func TestLoadWindowsTerminal(t *testing.T) {
+	var palettes, err = LoadWindowsTerminal(strings.NewReader(windowsTerminalSettings))
+	if err != nil || len(palettes) != 1 {
+		t.Fatal("Failed to load settings", err)
+	}
+}
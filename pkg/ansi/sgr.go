@@ -0,0 +1,217 @@
+package ansi
+
+import (
+	"errors"
+	"image/color"
+)
+
+// ErrInvalidExtended indicates that an extended colour code is missing or has invalid parameters.
+var ErrInvalidExtended = errors.New("invalid extended colour")
+
+// ErrInvalidUnderline indicates that an underline code has an unknown style.
+var ErrInvalidUnderline = errors.New("invalid underline style")
+
+// UnderlineStyle is the kind of underline selected by the UNDERLINE code and its sub-parameter.
+// DOUBLE_UNDERLINE and NOT_UNDERLINED are equivalent to the DoubleUnderline and NoUnderline styles.
+type UnderlineStyle byte
+
+const (
+	NoUnderline UnderlineStyle = iota
+	SingleUnderline
+	DoubleUnderline
+	CurlyUnderline
+	DottedUnderline
+	DashedUnderline
+)
+
+// Code is a single instruction parsed from an ANSI sequence.
+// Extended holds the colour selected by EXTENDED_TEXT and EXTENDED_BACKGROUND codes, and is nil otherwise.
+// Underline holds the style selected by UNDERLINE, DOUBLE_UNDERLINE and NOT_UNDERLINED codes.
+type Code struct {
+	Colour    Colour
+	Extended  color.Color
+	Underline UnderlineStyle
+}
+
+// ParseCodes will parse the codes from an ANSI sequence, grouping extended colours with their parameters.
+// Both the semicolon (38;2;r;g;b) and colon (38:2::r:g:b) forms are supported.
+// Errors are returned as a *ParseError.
+// It is meant to be used along with ansi.ScanCodes and ansi.IsSequence.
+func ParseCodes(sequence []byte) (res []Code, err error) {
+	return Strict.ParseCodes(sequence)
+}
+
+// ParseCodes will parse the codes from an ANSI sequence, grouping extended colours with their parameters.
+// In Lenient mode, codes that are out of range or have missing or invalid parameters are skipped.
+// Errors are returned as a *ParseError.
+func (m ParseMode) ParseCodes(sequence []byte) (res []Code, err error) {
+	return m.appendCodes(nil, sequence)
+}
+
+// appendCodes parses the codes from an ANSI sequence and appends them to dst.
+// It doesn't allocate unless dst runs out of capacity, the sequence is invalid or it has RGB colours.
+func (m ParseMode) appendCodes(dst []Code, sequence []byte) (res []Code, err error) {
+	res = dst
+
+	var buffer [MaxParameters]Parameter
+	var params []Parameter
+	params, err = m.AppendParameters(buffer[:0], sequence)
+	if err != nil {
+		return
+	}
+
+	if sequence[len(sequence)-1] != EndCode {
+		err = newParseError(sequence, len(sequence)-1, -1, CategoryUnknownFinal, ErrUnknownFinal)
+		return
+	}
+
+	for i := 0; i < len(params); {
+		var index = i
+		var n = groupLength(params[i:])
+		var sub = params[i+1 : i+n]
+		var at int
+
+		if params[i].Value > 255 {
+			if m.lenient() {
+				i += n
+				continue
+			}
+
+			err = newParseError(sequence, parameterOffset(sequence, i, m.c1()), i, CategoryOverflow, ErrByteOverflow)
+			return
+		}
+
+		var code = Code{Colour: Colour(params[i].Value)}
+		i += n
+
+		// Errors in the parameters of a code are reported at the offending parameter, counted from the code itself
+		switch code.Colour {
+		case EXTENDED_TEXT, EXTENDED_BACKGROUND:
+			if len(sub) > 0 {
+				code.Extended, at, err = parseExtendedSub(sub)
+			} else {
+				code.Extended, n, at, err = parseExtended(params[i:])
+				i += n
+			}
+		case UNDERLINE:
+			code.Underline, at, err = parseUnderline(sub)
+		case DOUBLE_UNDERLINE:
+			code.Underline = DoubleUnderline
+		}
+
+		if err != nil && m.lenient() {
+			err = nil
+			continue
+		}
+
+		if err != nil {
+			var category = CategoryInvalid
+			if errors.Is(err, ErrByteOverflow) {
+				category = CategoryOverflow
+			}
+
+			err = newParseError(sequence, parameterOffset(sequence, index+at, m.c1()), index+at, category, err)
+			return
+		}
+
+		res = append(res, code)
+	}
+
+	return
+}
+
+// MustParseCodes will parse the codes from an ANSI sequence.
+// It is meant to be used along with ansi.ScanCodes and ansi.IsSequence.
+// Panics if sequence is not valid.
+func MustParseCodes(sequence []byte) []Code {
+	var res, err = ParseCodes(sequence)
+	if err != nil {
+		panic(err)
+	}
+	return res
+}
+
+// parseExtended decodes the colour following an extended colour code in the semicolon form.
+// Returns how many parameters were consumed, even if they are invalid,
+// and on error the position of the offending parameter counted from the code, which is 0 if some are missing.
+func parseExtended(params []Parameter) (c color.Color, n, at int, err error) {
+	if len(params) == 0 {
+		err = ErrInvalidExtended
+		return
+	}
+
+	switch params[0].Value {
+	case ExtendedIndexed:
+		n = 2
+	case ExtendedRGB:
+		n = 4
+	default:
+		return nil, 1, 1, ErrInvalidExtended
+	}
+
+	if len(params) < n {
+		return nil, len(params), 0, ErrInvalidExtended
+	}
+
+	c, at, err = extendedColour(params[0].Value, params[1:n])
+	at += 2
+	return
+}
+
+// parseExtendedSub decodes the colour from the sub-parameters of an extended colour code in the colon form.
+// The RGB form may include a colour space identifier before the components, which is ignored.
+// On error, returns the position of the offending parameter counted from the code, which is 0 if some are missing.
+func parseExtendedSub(sub []Parameter) (c color.Color, at int, err error) {
+	var components []Parameter
+	switch {
+	case sub[0].Value == ExtendedIndexed && len(sub) >= 2:
+		at, components = 2, sub[1:2]
+	case sub[0].Value == ExtendedRGB && len(sub) >= 5:
+		at, components = 3, sub[2:5]
+	case sub[0].Value == ExtendedRGB && len(sub) == 4:
+		at, components = 2, sub[1:4]
+	case sub[0].Value != ExtendedIndexed && sub[0].Value != ExtendedRGB:
+		return nil, 1, ErrInvalidExtended
+	default:
+		return nil, 0, ErrInvalidExtended
+	}
+
+	var bad int
+	c, bad, err = extendedColour(sub[0].Value, components)
+	return c, at + bad, err
+}
+
+// extendedColour builds the colour of the given kind from its components.
+// On error, returns the position of the offending component.
+func extendedColour(kind int, components []Parameter) (c color.Color, at int, err error) {
+	for i, component := range components {
+		if component.Value > 255 {
+			return nil, i, ErrByteOverflow
+		}
+	}
+
+	if kind == ExtendedIndexed {
+		return Indexed(components[0].Value), 0, nil
+	}
+
+	return color.RGBA{
+		R: uint8(components[0].Value),
+		G: uint8(components[1].Value),
+		B: uint8(components[2].Value),
+		A: 255,
+	}, 0, nil
+}
+
+// parseUnderline decodes the style of an UNDERLINE code from its sub-parameters.
+// On error, returns the position of the offending parameter counted from the code.
+func parseUnderline(sub []Parameter) (style UnderlineStyle, at int, err error) {
+	if len(sub) == 0 {
+		return SingleUnderline, 0, nil
+	}
+
+	if sub[0].Value > int(DashedUnderline) {
+		return 0, 1, ErrInvalidUnderline
+	}
+
+	return UnderlineStyle(sub[0].Value), 0, nil
+}
@@ -0,0 +1,116 @@
+package ansi
+
+import (
+	"errors"
+	"image/color"
+	"testing"
+)
+
+func Test_ParseCodes(t *testing.T) {
+	t.Run(
+		"Indexed", func(t *testing.T) {
+			var actual = MustParseCodes([]byte("\x1b[1;38;5;208;48;5;21m"))
+
+			if len(actual) != 3 {
+				t.Fatal("Extended colour parameters aren't being grouped")
+			}
+
+			if actual[0].Colour != BOLD || actual[0].Extended != nil {
+				t.Fatal("Simple code parsed incorrectly")
+			}
+
+			if actual[1].Colour != EXTENDED_TEXT || actual[1].Extended != Indexed(208) {
+				t.Fatal("Indexed foreground parsed incorrectly")
+			}
+
+			if actual[2].Colour != EXTENDED_BACKGROUND || actual[2].Extended != Indexed(21) {
+				t.Fatal("Indexed background parsed incorrectly")
+			}
+		},
+	)
+
+	t.Run(
+		"RGB", func(t *testing.T) {
+			var actual = MustParseCodes([]byte("\x1b[38;2;255;128;0;4;48;2;1;2;3;91m"))
+
+			if len(actual) != 4 {
+				t.Fatal("Extended colour parameters aren't being grouped")
+			}
+
+			if actual[0].Colour != EXTENDED_TEXT || actual[0].Extended != (color.RGBA{R: 255, G: 128, A: 255}) {
+				t.Fatal("RGB foreground parsed incorrectly")
+			}
+
+			if actual[1].Colour != 4 {
+				t.Fatal("Code after RGB colour parsed incorrectly")
+			}
+
+			if actual[2].Colour != EXTENDED_BACKGROUND || actual[2].Extended != (color.RGBA{R: 1, G: 2, B: 3, A: 255}) {
+				t.Fatal("RGB background parsed incorrectly")
+			}
+
+			if actual[3].Colour != RED+HighIntensityOffset {
+				t.Fatal("Code after RGB colour parsed incorrectly")
+			}
+		},
+	)
+
+	t.Run(
+		"Incomplete", func(t *testing.T) {
+			var _, err = ParseCodes([]byte("\x1b[38;2;1;2m"))
+			if !errors.Is(err, ErrInvalidExtended) {
+				t.Fatal("Incomplete extended colour isn't an error")
+			}
+
+			_, err = ParseCodes([]byte("\x1b[38;5m"))
+			if !errors.Is(err, ErrInvalidExtended) {
+				t.Fatal("Incomplete extended colour isn't an error")
+			}
+		},
+	)
+
+	t.Run(
+		"Colon", func(t *testing.T) {
+			var sequences = [][]byte{
+				[]byte("\x1b[38:2::255:128:0;48:5:21;4:3m"),
+				[]byte("\x1b[38:2:255:128:0;48:5:21;4:3m"),
+				[]byte("\x1b[38:2:0:255:128:0;48;5;21;4:3m"),
+				[]byte("\x1b[38;2;255;128;0;48;5;21;4:3m"),
+			}
+
+			for _, sequence := range sequences {
+				var actual = MustParseCodes(sequence)
+
+				if len(actual) != 3 {
+					t.Fatalf("Sub-parameters aren't being grouped in %q", sequence)
+				}
+
+				if actual[0].Colour != EXTENDED_TEXT || actual[0].Extended != (color.RGBA{R: 255, G: 128, A: 255}) {
+					t.Fatalf("RGB foreground parsed incorrectly in %q", sequence)
+				}
+
+				if actual[1].Colour != EXTENDED_BACKGROUND || actual[1].Extended != Indexed(21) {
+					t.Fatalf("Indexed background parsed incorrectly in %q", sequence)
+				}
+
+				if actual[2].Colour != UNDERLINE || actual[2].Underline != CurlyUnderline {
+					t.Fatalf("Underline style parsed incorrectly in %q", sequence)
+				}
+			}
+		},
+	)
+
+	t.Run(
+		"Underline", func(t *testing.T) {
+			var actual = MustParseCodes([]byte("\x1b[4;4:0m"))
+			if actual[0].Underline != SingleUnderline || actual[1].Underline != NoUnderline {
+				t.Fatal("Underline style parsed incorrectly")
+			}
+
+			var _, err = ParseCodes([]byte("\x1b[4:9m"))
+			if !errors.Is(err, ErrInvalidUnderline) {
+				t.Fatal("Unknown underline style isn't an error")
+			}
+		},
+	)
+}
@@ -0,0 +1,172 @@
+package ansi
+
+import (
+	"bytes"
+	"io"
+	"strings"
+)
+
+// Strip returns a copy of data without any escape sequence, leaving only the text.
+// Control sequences, control strings such as OSC and DCS, and plain escape sequences such as ESC 7 or ESC ( B
+// are all removed, as are incomplete sequences at the end of data.
+// Control strings that are never terminated only lose their introducer, so the text after them isn't lost.
+// Other control characters, such as line feeds and tabs, are kept.
+func Strip(data []byte) []byte {
+	var s stripper
+	return s.strip(make([]byte, 0, len(data)), data, true)
+}
+
+// StripString returns s without any escape sequence, the same as ansi.Strip.
+func StripString(s string) string {
+	if strings.IndexByte(s, EscapeCode) < 0 {
+		return s
+	}
+	return string(Strip([]byte(s)))
+}
+
+// StripWriter removes every escape sequence from the data written to it, the same as ansi.Strip,
+// and writes the remaining text to the underlying writer.
+//
+// Sequences split across writes are held back until they are complete, or until they reach ansi.MaxScanLength,
+// so Flush or Close must be called once done to write anything that is left.
+type StripWriter struct {
+	w   io.Writer
+	s   stripper
+	out []byte
+}
+
+// NewStripWriter creates a StripWriter that writes to w.
+func NewStripWriter(w io.Writer) *StripWriter {
+	return &StripWriter{w: w}
+}
+
+// Write removes the escape sequences from data and writes the text to the underlying writer.
+// Data is always consumed, even if writing to the underlying writer fails.
+func (s *StripWriter) Write(data []byte) (n int, err error) {
+	return len(data), s.write(data, false)
+}
+
+// Flush writes the text held back by the writer, discarding any incomplete sequence the same as ansi.Strip.
+// Unlike ansi.DowngradeWriter, incomplete sequences are never written.
+func (s *StripWriter) Flush() error {
+	return s.write(nil, true)
+}
+
+// Close flushes the writer. It doesn't close the underlying writer.
+func (s *StripWriter) Close() error {
+	return s.Flush()
+}
+
+// write strips data and writes the text to the underlying writer.
+func (s *StripWriter) write(data []byte, atEOF bool) (err error) {
+	s.out = s.s.strip(s.out[:0], data, atEOF)
+	if len(s.out) > 0 {
+		_, err = s.w.Write(s.out)
+	}
+	return
+}
+
+// StripReader removes every escape sequence from the data read from the underlying reader, the same as ansi.Strip.
+type StripReader struct {
+	r    io.Reader
+	s    stripper
+	buf  []byte
+	out  []byte
+	read int
+	err  error
+}
+
+// NewStripReader creates a StripReader that reads from r.
+func NewStripReader(r io.Reader) *StripReader {
+	return &StripReader{r: r, buf: make([]byte, 4096)}
+}
+
+// Read reads text from the underlying reader into p, without any escape sequence.
+func (s *StripReader) Read(p []byte) (n int, err error) {
+	for s.read == len(s.out) {
+		if s.err != nil {
+			return 0, s.err
+		}
+
+		var m int
+		m, s.err = s.r.Read(s.buf)
+		s.out = s.s.strip(s.out[:0], s.buf[:m], s.err != nil)
+		s.read = 0
+	}
+
+	n = copy(p, s.out[s.read:])
+	s.read += n
+	return
+}
+
+// stripper removes escape sequences from a stream, holding back any incomplete sequence.
+type stripper struct {
+	tokens tokenizer
+}
+
+// strip appends the text in the pending data and data to dst, without any escape sequence.
+// Unless atEOF is set, incomplete sequences and UTF-8 characters at the end are kept for the next call.
+func (s *stripper) strip(dst, data []byte, atEOF bool) []byte {
+	s.tokens.push(data)
+
+	for token := s.tokens.next(atEOF); token != nil; token = s.tokens.next(atEOF) {
+		if Classify(token) != KindText {
+			continue
+		}
+
+		var n int
+		dst, n = stripText(dst, token, atEOF && s.tokens.empty())
+		if n < len(token) {
+			s.tokens.unread(len(token) - n)
+			break
+		}
+	}
+
+	return dst
+}
+
+// stripText appends text to dst without the plain escape sequences and malformed sequences in it.
+// If atEOF is set, text is the end of the stream, so an unterminated control string only loses its introducer,
+// keeping the text that follows it. Otherwise the control string was interrupted, and is removed.
+// Returns how much of text was used, which is less than all of it if it ends with an incomplete escape sequence
+// and atEOF isn't set.
+func stripText(dst, text []byte, atEOF bool) ([]byte, int) {
+	for i := 0; i < len(text); {
+		var escape = bytes.IndexByte(text[i:], EscapeCode)
+		if escape < 0 {
+			return append(dst, text[i:]...), len(text)
+		}
+
+		dst = append(dst, text[i:i+escape]...)
+		i += escape
+
+		var j = i + 1
+		switch {
+		case j < len(text) && isStringStart(text[j]):
+			// Control strings only reach here when they are interrupted, too long or unterminated at EOF
+			if !atEOF {
+				return dst, len(text)
+			}
+			j++
+		case j < len(text) && text[j] == StartCode:
+			// Malformed control sequences are removed up to the first invalid byte
+			for j++; j < len(text) && (isParameterByte(text[j]) || isIntermediateByte(text[j])); j++ {
+			}
+			if j < len(text) && isFinalByte(text[j]) {
+				j++
+			}
+		default:
+			for ; j < len(text) && isIntermediateByte(text[j]); j++ {
+			}
+			if j < len(text) && text[j] >= 0x30 && text[j] < deleteCode {
+				j++
+			} else if j == len(text) && !atEOF {
+				return dst, i
+			}
+		}
+
+		i = j
+	}
+
+	return dst, len(text)
+}
@@ -0,0 +1,195 @@
+package ansi
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+var stripTests = []struct {
+	name  string
+	input string
+	want  string
+}{
+	{name: "Plain text", input: "hello\tworld\n", want: "hello\tworld\n"},
+	{name: "Colours", input: "\x1b[1;31mred\x1b[0m and \x1b[38;2;1;2;3mrgb\x1b[m", want: "red and rgb"},
+	{name: "Cursor and erase", input: "\x1b[2J\x1b[Hhome\x1b[K\x1b[?25l", want: "home"},
+	{name: "Hyperlink", input: "\x1b]8;;https://example.com\x1b\\link\x1b]8;;\x07", want: "link"},
+	{name: "Control strings", input: "\x1bPq#0;2;0;0;0\x1b\\a\x1b_apc\x1b\\b\x1b^pm\x1b\\c\x1bXsos\x1b\\", want: "abc"},
+	{name: "Plain escapes", input: "\x1b7saved\x1b8\x1b(Bcharset\x1b=\x1b#8", want: "savedcharset"},
+	{name: "Unicode", input: "\x1b[32mñandú ✓\x1b[0m", want: "ñandú ✓"},
+	{name: "Malformed sequence", input: "\x1b[31\x01red", want: "\x01red"},
+	{name: "Escape before control", input: "a\x1b\nb", want: "a\nb"},
+	{name: "Unterminated string", input: "text\x1b]0;title\nline", want: "text0;title\nline"},
+	{name: "Interrupted string", input: "a\x1b]0;ti\x1b[0mtle", want: "atle"},
+	{name: "Incomplete sequence", input: "text\x1b[3", want: "text"},
+	{name: "Trailing escape", input: "text\x1b", want: "text"},
+}
+
+func TestStrip(t *testing.T) {
+	for _, test := range stripTests {
+		t.Run(
+			test.name, func(t *testing.T) {
+				if got := string(Strip([]byte(test.input))); got != test.want {
+					t.Fatalf("Stripped to %q instead of %q", got, test.want)
+				}
+				if got := StripString(test.input); got != test.want {
+					t.Fatalf("Stripped string to %q instead of %q", got, test.want)
+				}
+			},
+		)
+	}
+
+	t.Run(
+		"Input isn't modified", func(t *testing.T) {
+			var input = []byte("\x1b[31mred")
+			Strip(input)
+			if string(input) != "\x1b[31mred" {
+				t.Fatal("Input was modified")
+			}
+		},
+	)
+}
+
+func TestStripWriter(t *testing.T) {
+	for _, test := range stripTests {
+		t.Run(
+			test.name, func(t *testing.T) {
+				var out bytes.Buffer
+				var w = NewStripWriter(&out)
+
+				// Write a byte at a time to split every sequence
+				for i := range []byte(test.input) {
+					if n, err := w.Write([]byte{test.input[i]}); n != 1 || err != nil {
+						t.Fatal("Failed to write", n, err)
+					}
+				}
+				if err := w.Close(); err != nil {
+					t.Fatal("Failed to close", err)
+				}
+
+				if out.String() != test.want {
+					t.Fatalf("Stripped to %q instead of %q", out.String(), test.want)
+				}
+			},
+		)
+	}
+
+	t.Run(
+		"Held back sequence", func(t *testing.T) {
+			var out bytes.Buffer
+			var w = NewStripWriter(&out)
+			w.Write([]byte("a\x1b("))
+			if out.String() != "a" {
+				t.Fatal("Incomplete escape wasn't held back", out.String())
+			}
+			w.Write([]byte("Bb"))
+			if out.String() != "ab" {
+				t.Fatal("Split escape wasn't removed", out.String())
+			}
+		},
+	)
+
+	t.Run(
+		"Split writes", func(t *testing.T) {
+			var input = "\x1b[1;31mbuild \xf0\x9f\x98\x80 done\a\x1b[0m ok \x1b]8;;https://a\x1b\\ěĝ\x1b]8;;\x07 " +
+				"\x9b1b3b4 \x9d0;x\x9c \x1bPq\x1b\\\x1b(Bend\n"
+			var want = string(Strip([]byte(input)))
+
+			for i := 0; i <= len(input); i++ {
+				var out bytes.Buffer
+				var w = NewStripWriter(&out)
+				w.Write([]byte(input[:i]))
+				w.Write([]byte(input[i:]))
+				w.Close()
+
+				if out.String() != want {
+					t.Fatalf("Split at %d stripped to %q instead of %q", i, out.String(), want)
+				}
+			}
+		},
+	)
+
+	t.Run(
+		"Unterminated control string", func(t *testing.T) {
+			var out bytes.Buffer
+			var w = NewStripWriter(&out)
+			var line = strings.Repeat("x", 899) + "\n"
+
+			w.Write([]byte("a\x1b]0;x\n"))
+			for i := 0; i < 1000; i++ {
+				w.Write([]byte(line))
+			}
+
+			if out.Len() < 1000*len(line)-MaxScanLength {
+				t.Fatalf("Stray introducer held back the output, only %d bytes were written", out.Len())
+			}
+
+			w.Close()
+			if out.String() != "a0;x\n"+strings.Repeat(line, 1000) {
+				t.Fatal("Text after a stray introducer was lost")
+			}
+		},
+	)
+
+	t.Run(
+		"Held back character", func(t *testing.T) {
+			var out bytes.Buffer
+			var w = NewStripWriter(&out)
+			w.Write([]byte("a\xf0\x9f"))
+			if out.String() != "a" {
+				t.Fatal("Incomplete character wasn't held back", out.String())
+			}
+			w.Write([]byte("\x98\x80"))
+			if out.String() != "a\xf0\x9f\x98\x80" {
+				t.Fatal("Split character wasn't written", out.String())
+			}
+		},
+	)
+
+	t.Run(
+		"Write error", func(t *testing.T) {
+			if n, err := NewStripWriter(failingWriter{}).Write([]byte("text")); err == nil || n != 4 {
+				t.Fatal("Write error wasn't returned with the consumed data", n, err)
+			}
+		},
+	)
+}
+
+func TestStripReader(t *testing.T) {
+	for _, test := range stripTests {
+		t.Run(
+			test.name, func(t *testing.T) {
+				var r = NewStripReader(iotest.OneByteReader(strings.NewReader(test.input)))
+				var got, err = io.ReadAll(r)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if string(got) != test.want {
+					t.Fatalf("Stripped to %q instead of %q", got, test.want)
+				}
+			},
+		)
+	}
+
+	t.Run(
+		"Small reads", func(t *testing.T) {
+			var r = NewStripReader(strings.NewReader("\x1b[1mbold\x1b[0m text"))
+			var got, err = io.ReadAll(iotest.OneByteReader(r))
+			if err != nil || string(got) != "bold text" {
+				t.Fatalf("Stripped to %q with error %v", got, err)
+			}
+		},
+	)
+
+	t.Run(
+		"Read error", func(t *testing.T) {
+			var r = NewStripReader(iotest.ErrReader(iotest.ErrTimeout))
+			if _, err := r.Read(make([]byte, 8)); err != iotest.ErrTimeout {
+				t.Fatal("Read error wasn't returned", err)
+			}
+		},
+	)
+}
@@ -0,0 +1,316 @@
+package ansi
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ErrTerminfoNotFound indicates that there is no terminfo entry for a terminal in the search path.
+var ErrTerminfoNotFound = errors.New("terminfo entry not found")
+
+// ErrInvalidTerminfo indicates that a compiled terminfo entry is truncated or has an unknown format.
+var ErrInvalidTerminfo = errors.New("invalid terminfo entry")
+
+const (
+	// terminfoMagic starts entries with 16-bit numbers.
+	terminfoMagic = 0o432
+	// terminfoMagic32 starts entries with 32-bit numbers, written by ncurses 6.1 and later when needed.
+	terminfoMagic32 = 0o1036
+)
+
+// terminfoDirs are the system directories searched for terminfo entries, after the user's.
+var terminfoDirs = []string{"/etc/terminfo", "/lib/terminfo", "/usr/share/terminfo", "/usr/lib/terminfo"}
+
+// Terminfo is a compiled terminfo entry, describing the capabilities of a terminal.
+// Capabilities are keyed by their short name, such as colors or setaf, and extended capabilities
+// such as Tc and RGB are included with the standard ones.
+// Absent and cancelled capabilities aren't included.
+type Terminfo struct {
+	// Names are the names of the terminal, the last of which is usually a description.
+	Names   []string
+	Bools   map[string]bool
+	Numbers map[string]int
+	Strings map[string]string
+}
+
+// ParseTerminfo reads a compiled terminfo entry, in either the legacy or the 32-bit number format,
+// with or without the extended capabilities written by tic -x.
+func ParseTerminfo(data []byte) (t *Terminfo, err error) {
+	var r = terminfoReader{data: data}
+
+	var magic = r.short()
+	var width = 2
+	switch magic {
+	case terminfoMagic:
+	case terminfoMagic32:
+		width = 4
+	default:
+		return nil, fmt.Errorf("%w: unknown magic number %#o", ErrInvalidTerminfo, magic)
+	}
+
+	var namesSize, boolCount, numCount, strCount, tableSize = r.short(), r.short(), r.short(), r.short(), r.short()
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	t = &Terminfo{
+		Bools:   make(map[string]bool),
+		Numbers: make(map[string]int),
+		Strings: make(map[string]string),
+	}
+
+	var names = r.next(namesSize)
+	t.Names = strings.Split(string(bytes.TrimRight(names, "\x00")), "|")
+
+	var bools = r.bools(boolCount)
+	var numbers = r.numbers(numCount, width)
+	var strs = r.stringTable(strCount, tableSize)
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	t.add(terminfoBools[:], terminfoNumbers[:], terminfoStrings[:], bools, numbers, strs)
+
+	r.align()
+	if r.remaining() == 0 {
+		return
+	}
+
+	// Extended capabilities, which are followed by their names
+	var extBoolCount, extNumCount, extStrCount, _, extTableSize = r.short(), r.short(), r.short(), r.short(), r.short()
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	bools = r.bools(extBoolCount)
+	numbers = r.numbers(extNumCount, width)
+	var offsets = r.offsets(extStrCount)
+	var nameCount = extBoolCount + extNumCount + extStrCount
+	r.offsets(nameCount)
+	var table = r.next(extTableSize)
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	// The names are the last strings of the table, in the same order as the capabilities
+	var entries = strings.Split(strings.TrimSuffix(string(table), "\x00"), "\x00")
+	if len(entries) < nameCount {
+		return nil, fmt.Errorf("%w: missing extended capability names", ErrInvalidTerminfo)
+	}
+	var extNames = entries[len(entries)-nameCount:]
+
+	strs = make([]*string, extStrCount)
+	for i, offset := range offsets {
+		if strs[i], err = tableString(table, offset); err != nil {
+			return nil, err
+		}
+	}
+
+	t.add(extNames[:extBoolCount], extNames[extBoolCount:extBoolCount+extNumCount], extNames[extBoolCount+extNumCount:], bools, numbers, strs)
+	return
+}
+
+// LoadTerminfo finds and reads the terminfo entry of the terminal named term.
+// The environment variables in env are used for the search path, which is, in order,
+// $TERMINFO, $HOME/.terminfo, the directories in $TERMINFO_DIRS and then the system directories.
+// Returns an error wrapping ansi.ErrTerminfoNotFound if there is no entry for the terminal.
+func LoadTerminfo(term string, env map[string]string) (*Terminfo, error) {
+	if term == "" || strings.ContainsAny(term, "/\\") || term == "." || term == ".." {
+		return nil, fmt.Errorf("%w: %q", ErrTerminfoNotFound, term)
+	}
+
+	for _, dir := range terminfoPath(env) {
+		// Entries are stored under their first letter, or its hex code on case insensitive file systems
+		for _, sub := range []string{term[:1], strconv.FormatUint(uint64(term[0]), 16)} {
+			var data, err = os.ReadFile(filepath.Join(dir, sub, term))
+			if err == nil {
+				return ParseTerminfo(data)
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %q", ErrTerminfoNotFound, term)
+}
+
+// EnvironmentTerminfo reads the terminfo entry of the terminal named by $TERM, using the environment of the process.
+func EnvironmentTerminfo() (*Terminfo, error) {
+	var env = environment()
+	return LoadTerminfo(env["TERM"], env)
+}
+
+// terminfoPath returns the directories searched for terminfo entries.
+func terminfoPath(env map[string]string) (dirs []string) {
+	if dir := env["TERMINFO"]; dir != "" {
+		dirs = append(dirs, dir)
+	}
+	if home := env["HOME"]; home != "" {
+		dirs = append(dirs, filepath.Join(home, ".terminfo"))
+	}
+	if list, ok := env["TERMINFO_DIRS"]; ok {
+		for _, dir := range strings.Split(list, ":") {
+			// Empty entries stand for the system directories
+			if dir == "" {
+				dirs = append(dirs, terminfoDirs...)
+			} else {
+				dirs = append(dirs, dir)
+			}
+		}
+	}
+	return append(dirs, terminfoDirs...)
+}
+
+// Colours returns the number of colours the terminal supports, or 0 if it has none.
+func (t *Terminfo) Colours() int {
+	return max(t.Numbers["colors"], 0)
+}
+
+// Level returns the colour level of the terminal.
+// The Tc and RGB extended capabilities, or 2^24 colours, mean true colour support.
+func (t *Terminfo) Level() Level {
+	var _, rgb = t.Strings["RGB"]
+	var _, rgbNumber = t.Numbers["RGB"]
+
+	switch colours := t.Colours(); {
+	case t.Bools["Tc"] || t.Bools["RGB"] || rgb || rgbNumber || colours >= 1<<24:
+		return LevelTrueColour
+	case colours >= 256:
+		return Level256
+	case colours >= 8:
+		return Level16
+	default:
+		return LevelNone
+	}
+}
+
+// add stores the values of the capabilities with the given names.
+func (t *Terminfo) add(boolNames, numNames, strNames []string, bools []bool, numbers []int, strs []*string) {
+	for i, set := range bools {
+		if set && i < len(boolNames) {
+			t.Bools[boolNames[i]] = true
+		}
+	}
+	for i, n := range numbers {
+		if n >= 0 && i < len(numNames) {
+			t.Numbers[numNames[i]] = n
+		}
+	}
+	for i, s := range strs {
+		if s != nil && i < len(strNames) {
+			t.Strings[strNames[i]] = *s
+		}
+	}
+}
+
+// tableString returns the string at the offset of the string table, or nil if the capability is absent or cancelled.
+func tableString(table []byte, offset int) (*string, error) {
+	if offset < 0 {
+		return nil, nil
+	}
+	if offset >= len(table) {
+		return nil, fmt.Errorf("%w: string offset %d out of bounds", ErrInvalidTerminfo, offset)
+	}
+
+	var end = bytes.IndexByte(table[offset:], 0)
+	if end < 0 {
+		return nil, fmt.Errorf("%w: unterminated string", ErrInvalidTerminfo)
+	}
+
+	var s = string(table[offset : offset+end])
+	return &s, nil
+}
+
+// terminfoReader reads the sections of a compiled terminfo entry.
+// The first error is kept and any later read returns zero values.
+type terminfoReader struct {
+	data []byte
+	pos  int
+	err  error
+}
+
+// next returns the next n bytes, or nil if there aren't enough.
+func (r *terminfoReader) next(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if n < 0 || n > len(r.data)-r.pos {
+		r.err = fmt.Errorf("%w: truncated at byte %d", ErrInvalidTerminfo, r.pos)
+		return nil
+	}
+	var b = r.data[r.pos : r.pos+n]
+	r.pos += n
+	return b
+}
+
+// short reads a signed little endian 16-bit number.
+func (r *terminfoReader) short() int {
+	var b = r.next(2)
+	if b == nil {
+		return 0
+	}
+	return int(int16(binary.LittleEndian.Uint16(b)))
+}
+
+// bools reads n boolean capabilities, and the padding that aligns the numbers that follow.
+func (r *terminfoReader) bools(n int) (res []bool) {
+	for _, b := range r.next(n) {
+		res = append(res, b == 1)
+	}
+	r.align()
+	return
+}
+
+// numbers reads n numeric capabilities of the given width, where negative values are absent or cancelled.
+func (r *terminfoReader) numbers(n, width int) (res []int) {
+	var b = r.next(n * width)
+	for i := 0; i+width <= len(b); i += width {
+		if width == 4 {
+			res = append(res, int(int32(binary.LittleEndian.Uint32(b[i:]))))
+		} else {
+			res = append(res, int(int16(binary.LittleEndian.Uint16(b[i:]))))
+		}
+	}
+	return
+}
+
+// offsets reads n string table offsets, where negative values are absent or cancelled.
+func (r *terminfoReader) offsets(n int) (res []int) {
+	for i := 0; i < n && r.err == nil; i++ {
+		res = append(res, r.short())
+	}
+	return
+}
+
+// stringTable reads n string capabilities followed by their string table.
+func (r *terminfoReader) stringTable(n, tableSize int) (res []*string) {
+	var offsets = r.offsets(n)
+	var table = r.next(tableSize)
+	if r.err != nil {
+		return
+	}
+
+	res = make([]*string, n)
+	for i, offset := range offsets {
+		if res[i], r.err = tableString(table, offset); r.err != nil {
+			return
+		}
+	}
+	return
+}
+
+// align skips a byte to keep the position even.
+func (r *terminfoReader) align() {
+	if r.pos%2 == 1 && r.pos < len(r.data) {
+		r.pos++
+	}
+}
+
+// remaining returns the number of bytes left to read.
+func (r *terminfoReader) remaining() int {
+	return len(r.data) - r.pos
+}
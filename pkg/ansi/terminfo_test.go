@@ -0,0 +1,172 @@
+package ansi
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// readTerminfo parses a compiled terminfo fixture from testdata/terminfo.
+func readTerminfo(t *testing.T, name string) *Terminfo {
+	var data, err = os.ReadFile(filepath.Join("testdata", "terminfo", name[:1], name))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ti *Terminfo
+	if ti, err = ParseTerminfo(data); err != nil {
+		t.Fatal(err)
+	}
+	return ti
+}
+
+func TestParseTerminfo(t *testing.T) {
+	t.Run(
+		"Legacy format", func(t *testing.T) {
+			var ti = readTerminfo(t, "test-basic")
+			if len(ti.Names) != 2 || ti.Names[0] != "test-basic" || ti.Names[1] != "Test terminal with 8 colours" {
+				t.Fatal("Wrong names", ti.Names)
+			}
+			if !ti.Bools["am"] || !ti.Bools["xenl"] || ti.Bools["bw"] {
+				t.Fatal("Wrong boolean capabilities", ti.Bools)
+			}
+			if ti.Numbers["cols"] != 80 || ti.Numbers["lines"] != 24 || ti.Colours() != 8 {
+				t.Fatal("Wrong numeric capabilities", ti.Numbers)
+			}
+			if _, ok := ti.Numbers["lm"]; ok {
+				t.Fatal("Absent numeric capability was included")
+			}
+			if ti.Strings["setaf"] != "\x1b[3%p1%dm" || ti.Strings["sgr0"] != "\x1b[m" {
+				t.Fatal("Wrong string capabilities", ti.Strings)
+			}
+			if _, ok := ti.Strings["setf"]; ok {
+				t.Fatal("Absent string capability was included")
+			}
+		},
+	)
+
+	t.Run(
+		"Extended capabilities", func(t *testing.T) {
+			var ti = readTerminfo(t, "test-256color")
+			if !ti.Bools["Tc"] || ti.Strings["Smulx"] != "\x1b[4:%p1%dm" {
+				t.Fatal("Extended capabilities are missing", ti.Bools, ti.Strings)
+			}
+			if ti.Colours() != 256 || ti.Strings["sgr0"] != "\x1b[m" {
+				t.Fatal("Standard capabilities are wrong with extended capabilities")
+			}
+		},
+	)
+
+	t.Run(
+		"32-bit numbers", func(t *testing.T) {
+			var ti = readTerminfo(t, "test-direct")
+			if ti.Colours() != 1<<24 || ti.Numbers["pairs"] != 1<<16 || ti.Numbers["cols"] != 80 {
+				t.Fatal("Wrong 32-bit numbers", ti.Numbers)
+			}
+			if !ti.Bools["RGB"] {
+				t.Fatal("Extended capabilities are missing with 32-bit numbers")
+			}
+		},
+	)
+
+	t.Run(
+		"Invalid entries", func(t *testing.T) {
+			var data, err = os.ReadFile(filepath.Join("testdata", "terminfo", "t", "test-256color"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, invalid := range [][]byte{nil, {0x1A}, {0x1B, 0x01, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}, data[:40], data[:len(data)-3]} {
+				if _, err := ParseTerminfo(invalid); !errors.Is(err, ErrInvalidTerminfo) {
+					t.Fatalf("Invalid entry % x was parsed with error %v", invalid, err)
+				}
+			}
+		},
+	)
+}
+
+func TestTerminfo_Level(t *testing.T) {
+	var tests = map[string]Level{
+		"test-dumb":     LevelNone,
+		"test-basic":    Level16,
+		"test-256color": LevelTrueColour,
+		"test-direct":   LevelTrueColour,
+	}
+
+	for name, want := range tests {
+		if got := readTerminfo(t, name).Level(); got != want {
+			t.Fatalf("Terminal %s has level %s instead of %s", name, got, want)
+		}
+	}
+
+	var ti = readTerminfo(t, "test-256color")
+	delete(ti.Bools, "Tc")
+	if ti.Level() != Level256 {
+		t.Fatal("256 colour terminal without Tc isn't 256 colours")
+	}
+}
+
+func TestLoadTerminfo(t *testing.T) {
+	var testdata, _ = filepath.Abs(filepath.Join("testdata", "terminfo"))
+
+	t.Run(
+		"TERMINFO", func(t *testing.T) {
+			var ti, err = LoadTerminfo("test-basic", map[string]string{"TERMINFO": testdata})
+			if err != nil || ti.Names[0] != "test-basic" {
+				t.Fatal("Failed to load from TERMINFO", err)
+			}
+		},
+	)
+
+	t.Run(
+		"TERMINFO_DIRS", func(t *testing.T) {
+			var ti, err = LoadTerminfo("test-direct", map[string]string{"TERMINFO_DIRS": "/nonexistent:" + testdata})
+			if err != nil || ti.Names[0] != "test-direct" {
+				t.Fatal("Failed to load from TERMINFO_DIRS", err)
+			}
+		},
+	)
+
+	t.Run(
+		"Home directory", func(t *testing.T) {
+			var home = t.TempDir()
+			if err := os.Symlink(testdata, filepath.Join(home, ".terminfo")); err != nil {
+				t.Skip("Symlinks aren't supported", err)
+			}
+			var ti, err = LoadTerminfo("test-dumb", map[string]string{"HOME": home})
+			if err != nil || ti.Names[0] != "test-dumb" {
+				t.Fatal("Failed to load from the home directory", err)
+			}
+		},
+	)
+
+	t.Run(
+		"Hex directories", func(t *testing.T) {
+			var dir = t.TempDir()
+			var data, err = os.ReadFile(filepath.Join(testdata, "t", "test-basic"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err = os.Mkdir(filepath.Join(dir, "74"), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			if err = os.WriteFile(filepath.Join(dir, "74", "test-basic"), data, 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if _, err = LoadTerminfo("test-basic", map[string]string{"TERMINFO": dir}); err != nil {
+				t.Fatal("Failed to load from a hex directory", err)
+			}
+		},
+	)
+
+	t.Run(
+		"Not found", func(t *testing.T) {
+			for _, term := range []string{"test-missing", "", "../t/test-basic"} {
+				if _, err := LoadTerminfo(term, map[string]string{"TERMINFO": testdata}); !errors.Is(err, ErrTerminfoNotFound) {
+					t.Fatalf("Terminal %q was found with error %v", term, err)
+				}
+			}
+		},
+	)
+}
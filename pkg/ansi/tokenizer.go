@@ -0,0 +1,72 @@
+package ansi
+
+import "bytes"
+
+// tokenizer splits a stream written in chunks into the tokens of ansi.ScanCodes,
+// holding back incomplete sequences and UTF-8 characters until the rest of them is written.
+// Incomplete control strings are only scanned again once a byte that could end them is written,
+// or once they reach ansi.MaxScanLength, so long strings aren't scanned again on every write.
+type tokenizer struct {
+	pending []byte
+	read    int
+	// Length of the pending data when an incomplete control string was last scanned, or 0 if there isn't one
+	waiting int
+}
+
+// push adds data to the end of the stream, discarding the tokens that were already read.
+func (t *tokenizer) push(data []byte) {
+	if t.read > 0 {
+		t.pending = t.pending[:copy(t.pending, t.pending[t.read:])]
+		t.waiting = max(t.waiting-t.read, 0)
+		t.read = 0
+	}
+	t.pending = append(t.pending, data...)
+}
+
+// next returns the next token of the stream, or nil if there isn't a complete one.
+// Unless atEOF is set, incomplete sequences and UTF-8 characters at the end of the stream are held back.
+func (t *tokenizer) next(atEOF bool) []byte {
+	var data = t.pending[t.read:]
+	if len(data) == 0 {
+		return nil
+	}
+
+	if !atEOF && t.waiting > 0 && len(data) < MaxScanLength && !mayEndString(t.pending[t.waiting-1:]) {
+		return nil
+	}
+	t.waiting = 0
+
+	var advance, token, _ = ScanCodes(data, atEOF)
+	if advance == 0 {
+		if code, start := sequenceStart(data, false); start > 0 && code != StartCode {
+			t.waiting = len(t.pending)
+		}
+		return nil
+	}
+
+	if !atEOF && advance == len(data) && Classify(token) == KindText {
+		token = token[:len(token)-partialRuneLength(token)]
+		if len(token) == 0 {
+			return nil
+		}
+	}
+
+	t.read += len(token)
+	return token
+}
+
+// unread returns the last n bytes of the last token to the stream, to be read again once more data is written.
+func (t *tokenizer) unread(n int) {
+	t.read -= n
+}
+
+// empty checks if every token of the stream was read.
+func (t *tokenizer) empty() bool {
+	return t.read == len(t.pending)
+}
+
+// mayEndString checks if data has a byte that could end a control string.
+func mayEndString(data []byte) bool {
+	return bytes.IndexByte(data, BellCode) >= 0 || bytes.IndexByte(data, EscapeCode) >= 0 ||
+		bytes.IndexByte(data, StringTerminatorCode+C1Offset) >= 0
+}
@@ -0,0 +1,130 @@
+package ansi
+
+import (
+	"bufio"
+	"bytes"
+	"image/color"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ParseXresources reads the terminal colours of an X resources file, such as ~/.Xresources.
+// Resources are matched by their last component, so *color0, *.color0 and URxvt.color0 all set the first colour,
+// and later resources override earlier ones.
+// Colours are written as #RGB, #RRGGBB or rgb:r/g/b, and #define macros are substituted.
+// Files without a cursorColor use the foreground colour for the cursor.
+// The file doesn't name the scheme, so the palette is unnamed.
+func ParseXresources(data []byte) (p *Palette, err error) {
+	type resource struct {
+		value string
+		line  int
+	}
+
+	var resources = make(map[string]resource)
+	var defines = make(map[string]string)
+
+	var scanner = bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		var text = strings.TrimSpace(scanner.Text())
+
+		if name, ok := strings.CutPrefix(text, "#define"); ok {
+			var fields = strings.Fields(name)
+			if len(fields) >= 2 {
+				defines[fields[0]] = fields[1]
+			}
+			continue
+		}
+		if text == "" || text[0] == '!' || text[0] == '#' {
+			continue
+		}
+
+		var name, value, ok = strings.Cut(text, ":")
+		if !ok {
+			continue
+		}
+		name = strings.TrimSpace(name)
+		name = name[strings.LastIndexAny(name, ".*")+1:]
+		value = strings.TrimSpace(value)
+		if define, ok := defines[value]; ok {
+			value = define
+		}
+		resources[name] = resource{value: value, line: line}
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	var get = func(key string, dst *color.RGBA) error {
+		var r, ok = resources[key]
+		if !ok {
+			return &PaletteError{Key: key, Err: ErrMissingKey}
+		}
+		var c, err = parseXColour(r.value)
+		if err != nil {
+			return &PaletteError{Key: key, Line: r.line, Err: err}
+		}
+		*dst = c
+		return nil
+	}
+
+	p = &Palette{}
+	for i := range p.Colours {
+		if err = get("color"+strconv.Itoa(i), &p.Colours[i]); err != nil {
+			return nil, err
+		}
+	}
+	if err = get("foreground", &p.Foreground); err != nil {
+		return nil, err
+	}
+	if err = get("background", &p.Background); err != nil {
+		return nil, err
+	}
+
+	p.Cursor = p.Foreground
+	if _, ok := resources["cursorColor"]; ok {
+		if err = get("cursorColor", &p.Cursor); err != nil {
+			return nil, err
+		}
+	}
+
+	return
+}
+
+// LoadXresources reads the terminal colours of an X resources file from r.
+func LoadXresources(r io.Reader) (*Palette, error) {
+	var data, err = io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return ParseXresources(data)
+}
+
+// parseXColour reads a colour written as #RGB, #RRGGBB or rgb:r/g/b, with 1 to 4 hex digits per component.
+func parseXColour(s string) (c color.RGBA, err error) {
+	var spec, ok = strings.CutPrefix(s, "rgb:")
+	if !ok {
+		if !strings.HasPrefix(s, "#") {
+			return c, ErrInvalidColour
+		}
+		return parseHex(s)
+	}
+
+	var components = strings.Split(spec, "/")
+	if len(components) != 3 {
+		return c, ErrInvalidColour
+	}
+
+	var rgb [3]uint8
+	for i, component := range components {
+		var value, perr = strconv.ParseUint(component, 16, 16)
+		if perr != nil || len(component) == 0 || len(component) > 4 {
+			return c, ErrInvalidColour
+		}
+		// Scale to 8 bits, so that f, ff, fff and ffff are all 255
+		var max = uint64(1)<<(4*len(component)) - 1
+		rgb[i] = uint8((value*255 + max/2) / max)
+	}
+
+	return color.RGBA{R: rgb[0], G: rgb[1], B: rgb[2], A: 255}, nil
+}
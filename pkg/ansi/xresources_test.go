@@ -0,0 +1,113 @@
+package ansi
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+const xresources = `! Tango colours
+#define bg #2e3436
+
+Xft.dpi: 96
+*.foreground: #d3d7cf
+*.background: bg
+URxvt*cursorColor: rgb:d3/d7/cf
+*color0: bg
+*color1: #cc0000
+*.color2: #4e9a06
+*.color3: #c4a000
+*.color4: #3465a4
+*.color5: #75507b
+*.color6: #06989a
+*.color7: #d3d7cf
+*.color8: #555753
+*.color9: #ef2929
+*.color10: #8ae234
+*.color11: #fce94f
+*.color12: rgb:72/9f/cf
+*.color13: #ad7fa8
+*.color14: #34e2e2
+*.color15: rgb:eeee/eeee/eccc
+`
+
+func TestParseXresources(t *testing.T) {
+	t.Run(
+		"Colour scheme", func(t *testing.T) {
+			var p, err = ParseXresources([]byte(xresources))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if p.Colours != Tango.Colours {
+				t.Fatal("Wrong palette colours", p.Colours)
+			}
+			if p.Foreground != Tango.Foreground || p.Background != Tango.Background || p.Cursor != Tango.Cursor {
+				t.Fatal("Wrong default colours")
+			}
+		},
+	)
+
+	t.Run(
+		"Later resources win", func(t *testing.T) {
+			var p, err = ParseXresources([]byte(xresources + "XTerm*color1: #f00\n"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if p.Colours[1] != hex(0xFF0000) {
+				t.Fatal("Resource wasn't overridden", p.Colours[1])
+			}
+		},
+	)
+
+	t.Run(
+		"Missing key", func(t *testing.T) {
+			var _, err = ParseXresources([]byte(strings.Replace(xresources, "*.color13", "*.colour13", 1)))
+			var paletteErr *PaletteError
+			if !errors.As(err, &paletteErr) || !errors.Is(err, ErrMissingKey) || paletteErr.Key != "color13" {
+				t.Fatal("Missing key wasn't reported", err)
+			}
+		},
+	)
+
+	t.Run(
+		"Invalid colour", func(t *testing.T) {
+			var _, err = ParseXresources([]byte(strings.Replace(xresources, "rgb:72/9f/cf", "rgb:72/9f", 1)))
+			var paletteErr *PaletteError
+			if !errors.As(err, &paletteErr) || !errors.Is(err, ErrInvalidColour) {
+				t.Fatal("Invalid colour wasn't reported", err)
+			}
+			if paletteErr.Key != "color12" || paletteErr.Line != 20 {
+				t.Fatal("Error doesn't point at the invalid colour", err)
+			}
+		},
+	)
+}
+
+func Test_parseXColour(t *testing.T) {
+	var tests = []struct {
+		colour string
+		want   uint32
+		valid  bool
+	}{
+		{colour: "#123", want: 0x112233, valid: true},
+		{colour: "#A1B2C3", want: 0xA1B2C3, valid: true},
+		{colour: "rgb:f/8/0", want: 0xFF8800, valid: true},
+		{colour: "rgb:ffff/8080/0000", want: 0xFF8000, valid: true},
+		{colour: "rgb:fff/000/7ff", want: 0xFF007F, valid: true},
+		{colour: "red"},
+		{colour: "A1B2C3"},
+		{colour: "rgb:1/2/3/4"},
+		{colour: "rgb:12345/0/0"},
+		{colour: "rgb://"},
+	}
+
+	for _, test := range tests {
+		var c, err = parseXColour(test.colour)
+		if (err == nil) != test.valid {
+			t.Fatalf("Colour %q was parsed with error %v", test.colour, err)
+		}
+		if test.valid && c != hex(test.want) {
+			t.Fatalf("Colour %q was parsed as %v", test.colour, c)
+		}
+	}
+}